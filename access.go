@@ -0,0 +1,187 @@
+package gostream
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// The element access terminals are implemented by the pipeline, so the sequential and parallel streams behave the
+// same. The elements are pulled in encounter order, and the upstream stages are evaluated no further than needed.
+
+func (p *pipeline) FirstOrDefault(obj interface{}) error {
+	const op = "first or default"
+	dest, err := elementTarget(op, obj)
+	if err != nil {
+		return err
+	}
+	elements, err := p.head(1)
+	if err != nil {
+		return err
+	}
+	return assignElement(op, dest, elementOrNil(elements, 0))
+}
+
+func (p *pipeline) LastOrDefault(obj interface{}) error {
+	const op = "last or default"
+	dest, err := elementTarget(op, obj)
+	if err != nil {
+		return err
+	}
+	it := p.iterator()
+	var last *element
+	for e, ok := it.next(); ok; e, ok = it.next() {
+		last = e
+	}
+	if err := it.err(); err != nil {
+		p.record(err)
+		return err
+	}
+	return assignElement(op, dest, last)
+}
+
+func (p *pipeline) ElementAt(index int, obj interface{}) error {
+	const op = "element at"
+	dest, err := elementTarget(op, obj)
+	if err != nil {
+		return err
+	}
+	if index < 0 {
+		return fmt.Errorf("%s error, index is negative: %d", op, index)
+	}
+	// the elements before index are skipped rather than kept, only the element at index is needed
+	it := p.iterator()
+	length := 0
+	for e, ok := it.next(); ok; e, ok = it.next() {
+		if length == index {
+			return assignElement(op, dest, e)
+		}
+		length++
+	}
+	if err := it.err(); err != nil {
+		p.record(err)
+		return err
+	}
+	return fmt.Errorf("%s error, index out of range: %d with length %d", op, index, length)
+}
+
+func (p *pipeline) Single(obj interface{}) error {
+	const op = "single"
+	dest, err := elementTarget(op, obj)
+	if err != nil {
+		return err
+	}
+	elements, err := p.head(2)
+	if err != nil {
+		return err
+	}
+	if len(elements) == 0 {
+		return fmt.Errorf("%s error, the stream is empty", op)
+	}
+	if len(elements) > 1 {
+		return fmt.Errorf("%s error, the stream has more than one element", op)
+	}
+	return assignElement(op, dest, elements[0])
+}
+
+func (p *pipeline) SingleOrDefault(obj interface{}) error {
+	const op = "single or default"
+	dest, err := elementTarget(op, obj)
+	if err != nil {
+		return err
+	}
+	elements, err := p.head(2)
+	if err != nil {
+		return err
+	}
+	if len(elements) > 1 {
+		return fmt.Errorf("%s error, the stream has more than one element", op)
+	}
+	return assignElement(op, dest, elementOrNil(elements, 0))
+}
+
+// head pulls at most the first n elements of the pipeline, the error occurred is recorded so that Err can report it.
+func (p *pipeline) head(n int) ([]*element, error) {
+	it := p.iterator()
+	// n isn't the capacity, since it may be much larger than the stream
+	elements := make([]*element, 0)
+	for len(elements) < n {
+		e, ok := it.next()
+		if !ok {
+			break
+		}
+		elements = append(elements, e)
+	}
+	if err := it.err(); err != nil {
+		p.record(err)
+		return nil, err
+	}
+	return elements, nil
+}
+
+func (e *errStream) FirstOrDefault(interface{}) error {
+	return e.err
+}
+
+func (e *errStream) LastOrDefault(interface{}) error {
+	return e.err
+}
+
+func (e *errStream) ElementAt(int, interface{}) error {
+	return e.err
+}
+
+func (e *errStream) Single(interface{}) error {
+	return e.err
+}
+
+func (e *errStream) SingleOrDefault(interface{}) error {
+	return e.err
+}
+
+func elementOrNil(elements []*element, i int) *element {
+	if i < len(elements) {
+		return elements[i]
+	}
+	return nil
+}
+
+// elementTarget returns the value obj points to, an error is returned if obj isn't a non-nil pointer.
+func elementTarget(op string, obj interface{}) (reflect.Value, error) {
+	target := reflect.ValueOf(obj)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return reflect.Value{}, fmt.Errorf("%s error, obj is %T but not a non-nil pointer", op, obj)
+	}
+	return target.Elem(), nil
+}
+
+// assignElement sets dest to the data of e, or to the zero value if e is nil. A pointer is dereferenced if the value
+// it points to, rather than itself, is assignable to dest.
+func assignElement(op string, dest reflect.Value, e *element) error {
+	if e == nil {
+		dest.Set(reflect.Zero(dest.Type()))
+		return nil
+	}
+	val := elementValue(e)
+	switch {
+	case !val.IsValid():
+		if !isNilable(dest.Kind()) {
+			return fmt.Errorf("%s error, nil element is not assignable to %s", op, dest.Type())
+		}
+		dest.Set(reflect.Zero(dest.Type()))
+	case val.Type().AssignableTo(dest.Type()):
+		dest.Set(val)
+	case val.Kind() == reflect.Ptr && !val.IsNil() && val.Elem().Type().AssignableTo(dest.Type()):
+		dest.Set(val.Elem())
+	default:
+		return fmt.Errorf("%s error, element of type %s is not assignable to %s", op, val.Type(), dest.Type())
+	}
+	return nil
+}
+
+func isNilable(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package gostream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestStreamElementAccess(t *testing.T) {
+	for _, newStream := range []func([]*element) Stream{newSequentialStreamForTest, newParallelStreamForTest} {
+		s := newStream(intSliceToElements([]int{1, 2, 3}))
+		empty := newStream(nil)
+		single := newStream(intSliceToElements([]int{7}))
+
+		t.Run("test first or default", func(t *testing.T) {
+			dest := -1
+			assert.NoError(t, s.FirstOrDefault(&dest))
+			assert.Equal(t, 1, dest)
+			assert.NoError(t, empty.FirstOrDefault(&dest))
+			assert.Equal(t, 0, dest)
+		})
+
+		t.Run("test last or default", func(t *testing.T) {
+			dest := -1
+			assert.NoError(t, s.Map(func(src interface{}) (dest interface{}) {
+				return src.(int) * 10
+			}).LastOrDefault(&dest))
+			assert.Equal(t, 30, dest)
+			assert.NoError(t, empty.LastOrDefault(&dest))
+			assert.Equal(t, 0, dest)
+		})
+
+		t.Run("test element at", func(t *testing.T) {
+			var dest int
+			assert.NoError(t, s.ElementAt(1, &dest))
+			assert.Equal(t, 2, dest)
+			assert.EqualError(t, s.ElementAt(3, &dest), "element at error, index out of range: 3 with length 3")
+			assert.EqualError(t, s.ElementAt(-1, &dest), "element at error, index is negative: -1")
+			// the index doesn't size any allocation
+			assert.EqualError(t, s.ElementAt(math.MaxInt, &dest),
+				fmt.Sprintf("element at error, index out of range: %d with length 3", math.MaxInt))
+			assert.EqualError(t, s.ElementAt(1<<50, &dest), "element at error, index out of range: 1125899906842624 with length 3")
+			assert.Error(t, s.Skip(1).Limit(math.MaxInt).ElementAt(2, &dest))
+		})
+
+		t.Run("test single", func(t *testing.T) {
+			var dest int
+			assert.NoError(t, single.Single(&dest))
+			assert.Equal(t, 7, dest)
+			assert.EqualError(t, empty.Single(&dest), "single error, the stream is empty")
+			assert.EqualError(t, s.Single(&dest), "single error, the stream has more than one element")
+
+			dest = -1
+			assert.NoError(t, empty.SingleOrDefault(&dest))
+			assert.Equal(t, 0, dest)
+			assert.NoError(t, single.SingleOrDefault(&dest))
+			assert.Equal(t, 7, dest)
+			assert.EqualError(t, s.SingleOrDefault(&dest), "single or default error, the stream has more than one element")
+		})
+
+		t.Run("test type mismatch", func(t *testing.T) {
+			var str string
+			assert.EqualError(t, s.FirstOrDefault(&str), "first or default error, element of type int is not assignable to string")
+			assert.EqualError(t, s.LastOrDefault(str), "last or default error, obj is string but not a non-nil pointer")
+			var nilPointer *int
+			assert.EqualError(t, s.Single(nilPointer), "single error, obj is *int but not a non-nil pointer")
+			assert.EqualError(t, newStream([]*element{newElement(nil)}).FirstOrDefault(&str),
+				"first or default error, nil element is not assignable to string")
+			var any interface{}
+			assert.NoError(t, s.ElementAt(2, &any))
+			assert.Equal(t, 3, any)
+		})
+
+		t.Run("test error", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			var dest int
+			failed := s.WithContext(ctx)
+			for _, err := range []error{failed.FirstOrDefault(&dest), failed.LastOrDefault(&dest),
+				failed.ElementAt(0, &dest), failed.Single(&dest), failed.SingleOrDefault(&dest)} {
+				assert.Equal(t, context.Canceled, err)
+			}
+			assert.Equal(t, context.Canceled, failed.Err())
+		})
+	}
+
+	t.Run("test struct", func(t *testing.T) {
+		type model struct {
+			name string
+		}
+		models := []*model{{"mark"}, {"niko"}}
+		for _, s := range []Stream{NewSequentialStream(models), NewParallelStream(models)} {
+			var value model
+			assert.NoError(t, s.LastOrDefault(&value))
+			assert.Equal(t, model{"niko"}, value)
+
+			pointer := models[0]
+			assert.NoError(t, s.Filter(func(val interface{}) (match bool) {
+				return val.(*model).name == "jackson"
+			}).FirstOrDefault(&pointer))
+			assert.Nil(t, pointer)
+
+			assert.NoError(t, s.FirstOrDefault(&pointer))
+			assert.Same(t, models[0], pointer)
+		}
+	})
+
+	t.Run("test short-circuiting", func(t *testing.T) {
+		called := 0
+		s := Iterate(0, nil, func(prev interface{}) interface{} {
+			called++
+			return prev.(int) + 1
+		})
+		var dest int
+		assert.NoError(t, s.ElementAt(3, &dest))
+		assert.Equal(t, 3, dest)
+		assert.Equal(t, 3, called)
+		assert.Error(t, s.Single(&dest))
+		assert.Equal(t, 4, called)
+	})
+
+	e := errors.New("failed")
+	for _, s := range []Stream{&errStream{err: e}, &errStream{err: e, parallel: true}} {
+		var dest int
+		assert.Equal(t, e, s.FirstOrDefault(&dest))
+		assert.Equal(t, e, s.LastOrDefault(&dest))
+		assert.Equal(t, e, s.ElementAt(0, &dest))
+		assert.Equal(t, e, s.Single(&dest))
+		assert.Equal(t, e, s.SingleOrDefault(&dest))
+	}
+}
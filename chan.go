@@ -0,0 +1,246 @@
+package gostream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// chanIterator pulls the elements received from a channel until the channel is closed, an error is received from
+// the error channel of the producer, or the context is done.
+type chanIterator struct {
+	ctx   context.Context
+	cases []reflect.SelectCase
+	e     error
+	done  bool
+}
+
+const (
+	chanCaseElement = iota
+	chanCaseDone
+	chanCaseErr
+)
+
+// NewStreamFromChan returns a sequential ordered stream whose elements are received from ch until ch is closed,
+// ch should be a channel that can be received from.
+// The channel is only read when a terminal operation is called, and it can only be consumed once.
+func NewStreamFromChan(ch interface{}) Stream {
+	return NewStreamFromChanWithErr(ch, nil)
+}
+
+// NewStreamFromChanWithErr returns a sequential ordered stream whose elements are received from ch until ch is
+// closed, the producer reports its failure by sending an error to errc, which should be sent before closing ch.
+// The error received from errc stops the stream, and it's reported by the terminal operation and Err.
+func NewStreamFromChanWithErr(ch interface{}, errc <-chan error) Stream {
+	chValue := reflect.ValueOf(ch)
+	if chValue.Kind() != reflect.Chan || chValue.Type().ChanDir()&reflect.RecvDir == 0 {
+		return &errStream{err: errors.New("cannot new stream with non-receivable channel")}
+	}
+	return newSequentialStream(config{}, func(ctx context.Context) iterator {
+		return newChanIterator(ctx, chValue, errc)
+	})
+}
+
+// NewNumberStreamFromChan returns a sequential ordered stream whose elements are received from ch until ch is
+// closed. The channel is only read when a terminal operation is called, and it can only be consumed once.
+func NewNumberStreamFromChan[N Number](ch <-chan N) NumberStream[N] {
+	return NewNumberStreamFromChanWithErr(ch, nil)
+}
+
+// NewNumberStreamFromChanWithErr returns a sequential ordered stream whose elements are received from ch until ch is
+// closed, the producer reports its failure by sending an error to errc, which should be sent before closing ch.
+// The channel is read by the terminal operation like NewStreamFromChanWithErr, which stops once the context of the
+// stream is done.
+func NewNumberStreamFromChanWithErr[N Number](ch <-chan N, errc <-chan error) NumberStream[N] {
+	return newLazyNumberStream[N](NewStreamFromChanWithErr(ch, errc))
+}
+
+// NewIntStreamFromChan returns a sequential ordered stream whose elements are received from ch until ch is closed.
+// The channel is only read when a terminal operation is called.
+func NewIntStreamFromChan(ch <-chan int) IntStream {
+	return NewNumberStreamFromChan(ch)
+}
+
+// NewIntStreamFromChanWithErr returns a sequential ordered stream whose elements are received from ch until ch is
+// closed, the producer reports its failure by sending an error to errc, which should be sent before closing ch.
+// The channel is only read when a terminal operation is called.
+func NewIntStreamFromChanWithErr(ch <-chan int, errc <-chan error) IntStream {
+	return NewNumberStreamFromChanWithErr(ch, errc)
+}
+
+// NewFloat64StreamFromChan returns a sequential ordered stream whose elements are received from ch until ch is
+// closed. The channel is only read when a terminal operation is called.
+func NewFloat64StreamFromChan(ch <-chan float64) Float64Stream {
+	return NewNumberStreamFromChan(ch)
+}
+
+// NewFloat64StreamFromChanWithErr returns a sequential ordered stream whose elements are received from ch until ch
+// is closed, the producer reports its failure by sending an error to errc, which should be sent before closing ch.
+// The channel is only read when a terminal operation is called.
+func NewFloat64StreamFromChanWithErr(ch <-chan float64, errc <-chan error) Float64Stream {
+	return NewNumberStreamFromChanWithErr(ch, errc)
+}
+
+func newChanIterator(ctx context.Context, ch reflect.Value, errc <-chan error) *chanIterator {
+	// a nil channel is never ready, so the absent cases are simply nil channels
+	cases := []reflect.SelectCase{
+		chanCaseElement: {Dir: reflect.SelectRecv, Chan: ch},
+		chanCaseDone:    {Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+		chanCaseErr:     {Dir: reflect.SelectRecv, Chan: reflect.ValueOf(errc)},
+	}
+	return &chanIterator{ctx: ctx, cases: cases}
+}
+
+func (c *chanIterator) next() (*element, bool) {
+	for !c.done {
+		chosen, recv, ok := reflect.Select(c.cases)
+		switch {
+		case chosen == chanCaseDone:
+			c.e, c.done = c.ctx.Err(), true
+		case chosen == chanCaseErr && ok && !recv.IsNil():
+			c.e, c.done = recv.Interface().(error), true
+		case chosen == chanCaseErr:
+			// the producer closed errc without failure, keep receiving the elements
+			c.cases[chanCaseErr].Chan = reflect.ValueOf((<-chan error)(nil))
+		case !ok:
+			c.done = true
+			c.receiveErr()
+		default:
+			return &element{data: recv.Interface(), reflectValue: recv}, true
+		}
+	}
+	return nil, false
+}
+
+// receiveErr receives the error sent by the producer before closing the element channel, if any.
+func (c *chanIterator) receiveErr() {
+	cases := []reflect.SelectCase{c.cases[chanCaseErr], {Dir: reflect.SelectDefault}}
+	if chosen, recv, ok := reflect.Select(cases); chosen == 0 && ok && !recv.IsNil() {
+		c.e = recv.Interface().(error)
+	}
+}
+
+func (c *chanIterator) err() error {
+	return c.e
+}
+
+// ToChan sends the elements of the pipeline to ch in encounter order, and closes ch after all the elements were
+// sent or an error occurred.
+func (p *pipeline) ToChan(ch interface{}) (err error) {
+	chValue := reflect.ValueOf(ch)
+	if chValue.Kind() != reflect.Chan || chValue.Type().ChanDir()&reflect.SendDir == 0 {
+		return fmt.Errorf("cannot send results to ch, ch is a %T but not a sendable channel", ch)
+	}
+	defer chValue.Close()
+	defer func() {
+		// 当stream内的元素类型不能发送到ch中时会产生panic，要recover处理掉
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic when send results to channel, recover=%v", r)
+		}
+		p.record(err)
+	}()
+	cases := []reflect.SelectCase{
+		{Dir: reflect.SelectSend, Chan: chValue},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(p.context().Done())},
+	}
+	it := p.iterator()
+	for e, ok := it.next(); ok; e, ok = it.next() {
+		cases[0].Send = elementValue(e)
+		if chosen, _, _ := reflect.Select(cases); chosen == 1 {
+			return p.context().Err()
+		}
+	}
+	return it.err()
+}
+
+// Chan returns a channel receiving the elements of the pipeline in encounter order, the elements are evaluated in
+// a new goroutine, and the channel is closed after all the elements were sent or an error occurred.
+// The error can be obtained by Err after the channel was closed.
+func (p *pipeline) Chan() <-chan interface{} {
+	ch := make(chan interface{})
+	go func() {
+		defer close(ch)
+		ctx := p.context()
+		it := p.iterator()
+		for e, ok := it.next(); ok; e, ok = it.next() {
+			select {
+			case ch <- e.data:
+			case <-ctx.Done():
+				p.record(ctx.Err())
+				return
+			}
+		}
+		p.record(it.err())
+	}()
+	return ch
+}
+
+func (s *sequentialNumberStream[N]) ToChan(ch chan<- N) error {
+	defer close(ch)
+	for _, e := range s.elements {
+		select {
+		case ch <- e:
+		case <-doneOf(s.ctx):
+			return s.ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (s *sequentialNumberStream[N]) Chan() <-chan N {
+	return numbersToChan(s.ctx, s.elements)
+}
+
+func (p *parallelNumberStream[N]) ToChan(ch chan<- N) error {
+	defer close(ch)
+	for _, e := range p.elements {
+		select {
+		case ch <- e:
+		case <-doneOf(p.ctx):
+			return p.ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (p *parallelNumberStream[N]) Chan() <-chan N {
+	return numbersToChan(p.ctx, p.elements)
+}
+
+func (e *errNumberStream[N]) ToChan(ch chan<- N) error {
+	close(ch)
+	return e.err
+}
+
+func (e *errNumberStream[N]) Chan() <-chan N {
+	return numbersToChan[N](nil, nil)
+}
+
+func (e *errStream) ToChan(ch interface{}) error {
+	if chValue := reflect.ValueOf(ch); chValue.Kind() == reflect.Chan && chValue.Type().ChanDir()&reflect.SendDir != 0 {
+		chValue.Close()
+	}
+	return e.err
+}
+
+func (e *errStream) Chan() <-chan interface{} {
+	ch := make(chan interface{})
+	close(ch)
+	return ch
+}
+
+func numbersToChan[N Number](ctx context.Context, numbers []N) <-chan N {
+	ch := make(chan N)
+	go func() {
+		defer close(ch)
+		for _, e := range numbers {
+			select {
+			case ch <- e:
+			case <-doneOf(ctx):
+				return
+			}
+		}
+	}()
+	return ch
+}
@@ -0,0 +1,192 @@
+package gostream
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func produceInts(ints []int, err error) (<-chan int, <-chan error) {
+	ch := make(chan int)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(ch)
+		for _, i := range ints {
+			ch <- i
+		}
+		if err != nil {
+			errc <- err
+		}
+	}()
+	return ch, errc
+}
+
+func TestNewStreamFromChan(t *testing.T) {
+	t.Run("test not channel", func(t *testing.T) {
+		assert.Error(t, NewStreamFromChan([]int{1}).Err())
+	})
+	t.Run("test send-only channel", func(t *testing.T) {
+		assert.Error(t, NewStreamFromChan(make(chan<- int)).Err())
+	})
+	t.Run("test normal", func(t *testing.T) {
+		ch, _ := produceInts([]int{1, 2, 3}, nil)
+		var dest []int
+		err := NewStreamFromChan(ch).Map(func(src interface{}) (dest interface{}) {
+			return src.(int) * 2
+		}).Collect(&dest)
+		assert.NoError(t, err)
+		assertSliceEquals(t, []int{2, 4, 6}, dest)
+	})
+	t.Run("test parallel", func(t *testing.T) {
+		ch, _ := produceInts([]int{1, 2, 3}, nil)
+		var dest []int
+		s := NewStreamFromChan(ch).Parallel().Map(func(src interface{}) (dest interface{}) {
+			return src.(int) * 2
+		})
+		assert.True(t, s.IsParallel())
+		assert.NoError(t, s.Collect(&dest))
+		assertSliceEquals(t, []int{2, 4, 6}, dest)
+	})
+	t.Run("test producer error", func(t *testing.T) {
+		ch, errc := produceInts([]int{1, 2, 3}, errors.New("producer failed"))
+		s := NewStreamFromChanWithErr(ch, errc)
+		var dest []int
+		assert.EqualError(t, s.Collect(&dest), "producer failed")
+		assert.EqualError(t, s.Err(), "producer failed")
+	})
+	t.Run("test error channel closed", func(t *testing.T) {
+		ch := make(chan int, 2)
+		errc := make(chan error)
+		ch <- 1
+		ch <- 2
+		close(errc)
+		close(ch)
+		var dest []int
+		assert.NoError(t, NewStreamFromChanWithErr(ch, errc).Collect(&dest))
+		assertSliceEquals(t, []int{1, 2}, dest)
+	})
+}
+
+func TestNewIntStreamFromChan(t *testing.T) {
+	ch, _ := produceInts([]int{1, 2, 3}, nil)
+	res, err := NewIntStreamFromChan(ch).Collect()
+	assert.NoError(t, err)
+	assertSliceEquals(t, []int{1, 2, 3}, res)
+
+	ch, errc := produceInts([]int{1, 2, 3}, errors.New(""))
+	s := NewIntStreamFromChanWithErr(ch, errc)
+	_, err = s.Sum()
+	assert.Error(t, err)
+	assert.Error(t, s.Err())
+
+	t.Run("test context canceled", func(t *testing.T) {
+		// the producer is still open, the stream is only read by the terminal operation, which stops on cancellation
+		ch := make(chan int, 1)
+		ch <- 1
+		s := NewIntStreamFromChan(ch)
+		ctx, cancel := context.WithCancel(context.Background())
+		var received int32
+		go func() {
+			for atomic.LoadInt32(&received) == 0 {
+				time.Sleep(time.Millisecond)
+			}
+			cancel()
+		}()
+		_, err := s.WithContext(ctx).Peek(func(val int) {
+			atomic.StoreInt32(&received, 1)
+		}).Collect()
+		assert.Equal(t, context.Canceled, err)
+		close(ch)
+	})
+}
+
+func TestNewFloat64StreamFromChan(t *testing.T) {
+	ch := make(chan float64, 2)
+	ch <- 1
+	ch <- 2
+	close(ch)
+	res, err := NewFloat64StreamFromChan(ch).Collect()
+	assert.NoError(t, err)
+	assertFloat64SliceEquals(t, []float64{1, 2}, res)
+}
+
+func TestStreamToChan(t *testing.T) {
+	for _, newStream := range []func([]*element) Stream{newSequentialStreamForTest, newParallelStreamForTest} {
+		ch := make(chan int)
+		errc := make(chan error, 1)
+		go func() {
+			errc <- newStream(intSliceToElements([]int{1, 2, 3})).ToChan(ch)
+		}()
+		var res []int
+		for i := range ch {
+			res = append(res, i)
+		}
+		assert.NoError(t, <-errc)
+		assertSliceEquals(t, []int{1, 2, 3}, res)
+	}
+
+	t.Run("test not channel", func(t *testing.T) {
+		assert.Error(t, NewSequentialStream([]int{1}).ToChan([]int{}))
+	})
+	t.Run("test incorrect element type", func(t *testing.T) {
+		assert.Error(t, NewSequentialStream([]int{1}).ToChan(make(chan string, 1)))
+	})
+	t.Run("test error stream", func(t *testing.T) {
+		ch := make(chan int)
+		assert.Error(t, testErrStream.ToChan(ch))
+		_, ok := <-ch
+		assert.False(t, ok)
+	})
+}
+
+func TestStreamChan(t *testing.T) {
+	s := NewSequentialStream([]int{1, 2, 3}).FlatMap(func(val interface{}) Stream {
+		if val.(int) == 3 {
+			return &errStream{err: errors.New("")}
+		}
+		return NewSequentialStream([]int{val.(int)})
+	})
+	var res []int
+	for e := range s.Chan() {
+		res = append(res, e.(int))
+	}
+	assertSliceEquals(t, []int{1, 2}, res)
+	assert.Error(t, s.Err())
+
+	_, ok := <-testErrStream.Chan()
+	assert.False(t, ok)
+}
+
+func TestIntStreamChan(t *testing.T) {
+	for _, s := range []IntStream{NewSequentialIntStream([]int{1, 2}), NewParallelIntStream([]int{1, 2})} {
+		var res []int
+		for e := range s.Chan() {
+			res = append(res, e)
+		}
+		assertSliceEquals(t, []int{1, 2}, res)
+
+		ch := make(chan int, 2)
+		assert.NoError(t, s.ToChan(ch))
+		assert.Equal(t, 1, <-ch)
+		assert.Equal(t, 2, <-ch)
+	}
+	assert.Error(t, testErrIntStream.ToChan(make(chan int)))
+}
+
+func TestFloat64StreamChan(t *testing.T) {
+	for _, s := range []Float64Stream{NewSequentialFloat64Stream([]float64{1, 2}), NewParallelFloat64Stream([]float64{1, 2})} {
+		var res []float64
+		for e := range s.Chan() {
+			res = append(res, e)
+		}
+		assertFloat64SliceEquals(t, []float64{1, 2}, res)
+
+		ch := make(chan float64, 2)
+		assert.NoError(t, s.ToChan(ch))
+		assert.Equal(t, 1.0, <-ch)
+	}
+	assert.Error(t, testSerialErrFloat64Stream.ToChan(make(chan float64)))
+}
@@ -0,0 +1,114 @@
+// Command streamgen generates the specialized NumberStream of a numeric type, such as the IntStream of int, and its
+// tests. It only generates an alias of NumberStream[T] and the constructors of the alias, since the generic number
+// streams already provide the sequential, parallel and error implementations for every numeric type, so there's no
+// per-type implementation to generate. It's meant to be run by go generate in the package declaring the type:
+//
+//	type Money int64
+//
+//	//go:generate go run github.com/gaojunhuicavon/gostream/cmd/streamgen -type Money -tests
+//
+// which writes money_stream.go declaring MoneyStream, NewSequentialMoneyStream, NewParallelMoneyStream,
+// ConcatMoneyStream and ZipMoney, and money_stream_gen_test.go. The underlying type of the element type should be an
+// integer or floating-point type, and the type should be predeclared or declared in the package.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+// params are the parameters of the templates.
+type params struct {
+	// Package is the package of the generated files.
+	Package string
+	// Type is the element type.
+	Type string
+	// Name is the name prefix of the declarations, such as Int of IntStream.
+	Name string
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("streamgen: ")
+	typ := flag.String("type", "", "the element type, whose underlying type is an integer or floating-point type")
+	name := flag.String("name", "", "the name prefix of the declarations, the type with the first letter upper-cased "+
+		"by default")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "the package of the generated files, $GOPACKAGE by default")
+	output := flag.String("output", "", "the output file, <lower-cased name>_stream.go by default")
+	tests := flag.Bool("tests", false, "generate the tests as well into <output>_gen_test.go, which depend on "+
+		"github.com/stretchr/testify")
+	flag.Parse()
+	if *typ == "" || *pkg == "" || flag.NArg() > 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	p := params{Package: *pkg, Type: *typ, Name: *name}
+	if p.Name == "" {
+		p.Name = upperFirst(p.Type)
+	}
+	if *output == "" {
+		*output = strings.ToLower(p.Name) + "_stream.go"
+	}
+	if err := write(*output, streamTemplate, p); err != nil {
+		log.Fatal(err)
+	}
+	if *tests {
+		if err := write(strings.TrimSuffix(*output, ".go")+"_gen_test.go", testTemplate, p); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func write(filename string, tmpl *template.Template, p params) error {
+	src, err := generate(tmpl, p)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, src, 0644)
+}
+
+// generate executes tmpl with p, and returns the formatted source.
+func generate(tmpl *template.Template, p params) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, p); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format %s error, %w", tmpl.Name(), err)
+	}
+	return src, nil
+}
+
+// Internal reports whether the files are generated into gostream itself.
+func (p params) Internal() bool {
+	return p.Package == "gostream"
+}
+
+// Qualifier returns the qualifier of the declarations of gostream.
+func (p params) Qualifier() string {
+	if p.Internal() {
+		return ""
+	}
+	return "gostream."
+}
+
+// Plural returns the name of a slice of the elements, such as ints.
+func (p params) Plural() string {
+	r, size := utf8.DecodeRuneInString(p.Name)
+	return string(unicode.ToLower(r)) + p.Name[size:] + "s"
+}
+
+func upperFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
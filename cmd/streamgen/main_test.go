@@ -0,0 +1,54 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+)
+
+func TestGenerateUpToDate(t *testing.T) {
+	tests := []struct {
+		typ, output string
+	}{
+		{"int", "int_stream.go"},
+		{"int64", "int64_stream.go"},
+		{"uint64", "uint64_stream.go"},
+		{"float64", "f64_stream.go"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ, func(t *testing.T) {
+			p := params{Package: "gostream", Type: tt.typ, Name: upperFirst(tt.typ)}
+			for filename, tmpl := range map[string]*template.Template{
+				tt.output: streamTemplate,
+				tt.output[:len(tt.output)-len(".go")] + "_gen_test.go": testTemplate,
+			} {
+				expect, err := os.ReadFile(filepath.Join("..", "..", filename))
+				assert.NoError(t, err)
+				src, err := generate(tmpl, p)
+				assert.NoError(t, err)
+				assert.Equal(t, string(expect), string(src), "%s is out of date, run go generate", filename)
+			}
+		})
+	}
+}
+
+func TestGenerateExternal(t *testing.T) {
+	p := params{Package: "billing", Type: "Money", Name: "Money"}
+	for _, tmpl := range []*template.Template{streamTemplate, testTemplate} {
+		src, err := generate(tmpl, p)
+		assert.NoError(t, err)
+		f, err := parser.ParseFile(token.NewFileSet(), "", src, parser.ImportsOnly)
+		assert.NoError(t, err)
+		assert.Equal(t, "billing", f.Name.Name)
+		assert.Contains(t, string(src), `"github.com/gaojunhuicavon/gostream"`)
+	}
+	src, err := generate(streamTemplate, p)
+	assert.NoError(t, err)
+	assert.Contains(t, string(src), "type MoneyStream = gostream.NumberStream[Money]")
+	assert.Contains(t, string(src), "func NewSequentialMoneyStream(moneys []Money) MoneyStream {")
+	assert.NotContains(t, string(src), "sequentialNumberStream")
+}
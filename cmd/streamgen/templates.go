@@ -0,0 +1,129 @@
+package main
+
+import "text/template"
+
+var streamTemplate = template.Must(template.New("stream").Parse(`// Code generated by streamgen; DO NOT EDIT.
+
+package {{.Package}}
+{{if not .Internal}}
+import "github.com/gaojunhuicavon/gostream"
+{{end}}
+{{$q := .Qualifier}}
+// {{.Name}}Stream is a sequence of {{.Type}}-valued elements supporting sequential and parallel aggregate operations.
+type {{.Name}}Stream = {{$q}}NumberStream[{{.Type}}]
+{{if .Internal}}
+type sequential{{.Name}}Stream = sequentialNumberStream[{{.Type}}]
+
+type parallel{{.Name}}Stream = parallelNumberStream[{{.Type}}]
+
+type err{{.Name}}Stream = errNumberStream[{{.Type}}]
+{{end}}
+// NewSequential{{.Name}}Stream returns a sequential ordered stream whose elements are the specified {{.Plural}}.
+func NewSequential{{.Name}}Stream({{.Plural}} []{{.Type}}) {{.Name}}Stream {
+	return {{$q}}NewSequentialNumberStream({{.Plural}})
+}
+
+// NewParallel{{.Name}}Stream returns a parallel stream whose elements are the specified {{.Plural}}.
+func NewParallel{{.Name}}Stream({{.Plural}} []{{.Type}}) {{.Name}}Stream {
+	return {{$q}}NewParallelNumberStream({{.Plural}})
+}
+
+// Concat{{.Name}}Stream creates a concatenated sequential stream whose elements are all the elements of the first
+// stream followed by all the elements of the second stream.
+func Concat{{.Name}}Stream(a, b {{.Name}}Stream) {{.Name}}Stream {
+	return {{$q}}ConcatNumberStream(a, b)
+}
+
+// Zip{{.Name}} creates a sequential {{.Name}}Stream whose elements are the results of applying combiner to the
+// elements of a and b at the same position, the stream is as long as the shorter one of a and b.
+func Zip{{.Name}}(a, b {{.Name}}Stream, combiner func(first, second {{.Type}}) {{.Type}}) {{.Name}}Stream {
+	return {{$q}}ZipNumber(a, b, combiner)
+}
+`))
+
+var testTemplate = template.Must(template.New("test").Parse(`// Code generated by streamgen; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+	{{if not .Internal}}"github.com/gaojunhuicavon/gostream"
+	{{end}}"github.com/stretchr/testify/assert"
+	"testing"
+)
+{{$q := .Qualifier}}
+func Test{{.Name}}StreamGenerated(t *testing.T) {
+	for _, newStream := range []func([]{{.Type}}) {{.Name}}Stream{NewSequential{{.Name}}Stream, NewParallel{{.Name}}Stream} {
+		s := newStream([]{{.Type}}{3, 1, 2, 3})
+
+		res, err := s.Map(func(src {{.Type}}) (dest {{.Type}}) {
+			return src * 2
+		}).Filter(func(val {{.Type}}) (match bool) {
+			return val > 2
+		}).Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []{{.Type}}{6, 4, 6}, res)
+
+		res, err = s.Distinct().Sorted().Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []{{.Type}}{1, 2, 3}, res)
+
+		sum, err := s.Sum()
+		assert.NoError(t, err)
+		assert.Equal(t, {{.Type}}(9), sum)
+		sum, err = s.SumChecked()
+		assert.NoError(t, err)
+		assert.Equal(t, {{.Type}}(9), sum)
+
+		average, err := s.Average()
+		assert.NoError(t, err)
+		assert.Equal(t, 2.25, *average)
+
+		min, err := s.Min()
+		assert.NoError(t, err)
+		assert.Equal(t, {{.Type}}(1), *min)
+		max, err := s.Max()
+		assert.NoError(t, err)
+		assert.Equal(t, {{.Type}}(3), *max)
+
+		reduced, err := s.Skip(1).Limit(2).Reduce(func(a, b {{.Type}}) (c {{.Type}}) {
+			return a + b
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, {{.Type}}(3), *reduced)
+
+		stats, err := s.SummaryStatistics()
+		assert.NoError(t, err)
+		assert.Equal(t, 4, stats.Count())
+		assert.Equal(t, {{.Type}}(9), stats.Sum())
+
+		empty := newStream(nil)
+		res, err = empty.Collect()
+		assert.NoError(t, err)
+		assert.Empty(t, res)
+		average, err = empty.Average()
+		assert.NoError(t, err)
+		assert.Nil(t, average)
+		assert.Equal(t, s.IsParallel(), empty.IsParallel())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = s.WithContext(ctx).Collect()
+		assert.Equal(t, context.Canceled, err)
+		_, err = s.Limit(-1).Sum()
+		assert.Error(t, err)
+	}
+
+	res, err := Concat{{.Name}}Stream(NewSequential{{.Name}}Stream([]{{.Type}}{1, 2}), NewParallel{{.Name}}Stream([]{{.Type}}{3})).Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []{{.Type}}{1, 2, 3}, res)
+
+	res, err = Zip{{.Name}}(NewSequential{{.Name}}Stream([]{{.Type}}{1, 2, 3}), NewParallel{{.Name}}Stream([]{{.Type}}{4, 5}), func(first, second {{.Type}}) {{.Type}} {
+		return first + second
+	}).Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []{{.Type}}{5, 7}, res)
+
+	var _ {{$q}}NumberStream[{{.Type}}] = NewSequential{{.Name}}Stream(nil)
+}
+`))
@@ -0,0 +1,375 @@
+package gostream
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Collector is a mutable reduction operation that accumulates the elements of a stream into a result container, and
+// optionally transforms the container into a final result after all the elements were accumulated.
+// A parallel stream accumulates its elements into several containers concurrently, then merges them with Combine.
+type Collector interface {
+	// Supply returns a new empty result container.
+	Supply() interface{}
+	// Accumulate folds an element into container, and returns the container, which may be a new one if the container
+	// is immutable, such as a number.
+	Accumulate(container, element interface{}) interface{}
+	// Combine merges the container b, which holds the elements encountered after the elements of a, into a, and
+	// returns the merged container.
+	Combine(a, b interface{}) interface{}
+	// Finish transforms the container into the final result.
+	Finish(container interface{}) interface{}
+}
+
+type collector struct {
+	supplier    func() interface{}
+	accumulator func(container, element interface{}) interface{}
+	combiner    func(a, b interface{}) interface{}
+	finisher    func(container interface{}) interface{}
+}
+
+// averaging is the container of Averaging.
+type averaging struct {
+	sum   float64
+	count int
+}
+
+// NewCollector returns a Collector described by the given functions, the container is returned as the result if
+// finisher is nil.
+func NewCollector(
+	supplier func() interface{},
+	accumulator func(container, element interface{}) interface{},
+	combiner func(a, b interface{}) interface{},
+	finisher func(container interface{}) interface{},
+) Collector {
+	return &collector{supplier: supplier, accumulator: accumulator, combiner: combiner, finisher: finisher}
+}
+
+// ToSlice returns a Collector that accumulates the elements into a []interface{} in encounter order.
+func ToSlice() Collector {
+	return NewCollector(
+		func() interface{} {
+			return make([]interface{}, 0)
+		},
+		func(container, element interface{}) interface{} {
+			return append(container.([]interface{}), element)
+		},
+		func(a, b interface{}) interface{} {
+			return append(a.([]interface{}), b.([]interface{})...)
+		},
+		nil,
+	)
+}
+
+// GroupingBy returns a Collector that groups the elements by the key returned by classifier, and accumulates the
+// elements of each group with downstream. The result is a map[interface{}]interface{} from the keys to the results of
+// downstream, ToSlice is used if downstream is nil.
+func GroupingBy(classifier func(element interface{}) (key interface{}), downstream Collector) Collector {
+	if downstream == nil {
+		downstream = ToSlice()
+	}
+	return NewCollector(
+		func() interface{} {
+			return make(map[interface{}]interface{})
+		},
+		func(container, element interface{}) interface{} {
+			groups := container.(map[interface{}]interface{})
+			key := classifier(element)
+			group, ok := groups[key]
+			if !ok {
+				group = downstream.Supply()
+			}
+			groups[key] = downstream.Accumulate(group, element)
+			return groups
+		},
+		func(a, b interface{}) interface{} {
+			groups := a.(map[interface{}]interface{})
+			for key, group := range b.(map[interface{}]interface{}) {
+				if existing, ok := groups[key]; ok {
+					group = downstream.Combine(existing, group)
+				}
+				groups[key] = group
+			}
+			return groups
+		},
+		func(container interface{}) interface{} {
+			groups := container.(map[interface{}]interface{})
+			for key, group := range groups {
+				groups[key] = downstream.Finish(group)
+			}
+			return groups
+		},
+	)
+}
+
+// PartitioningBy returns a Collector that partitions the elements by predicate. The result is a map[bool][]interface{},
+// which always contains the partitions of both true and false.
+func PartitioningBy(predicate func(element interface{}) (match bool)) Collector {
+	return PartitioningByWith(predicate, nil)
+}
+
+// PartitioningByWith returns a Collector that partitions the elements by predicate, and accumulates the elements of
+// each partition with downstream. The result is a map[bool]interface{} from true and false to the results of
+// downstream, ToSlice is used if downstream is nil.
+func PartitioningByWith(predicate func(element interface{}) (match bool), downstream Collector) Collector {
+	toSlice := downstream == nil
+	if toSlice {
+		downstream = ToSlice()
+	}
+	return NewCollector(
+		func() interface{} {
+			return map[bool]interface{}{true: downstream.Supply(), false: downstream.Supply()}
+		},
+		func(container, element interface{}) interface{} {
+			partitions := container.(map[bool]interface{})
+			match := predicate(element)
+			partitions[match] = downstream.Accumulate(partitions[match], element)
+			return partitions
+		},
+		func(a, b interface{}) interface{} {
+			partitions := a.(map[bool]interface{})
+			for match, partition := range b.(map[bool]interface{}) {
+				partitions[match] = downstream.Combine(partitions[match], partition)
+			}
+			return partitions
+		},
+		func(container interface{}) interface{} {
+			partitions := container.(map[bool]interface{})
+			if toSlice {
+				return map[bool][]interface{}{
+					true:  downstream.Finish(partitions[true]).([]interface{}),
+					false: downstream.Finish(partitions[false]).([]interface{}),
+				}
+			}
+			return map[bool]interface{}{
+				true:  downstream.Finish(partitions[true]),
+				false: downstream.Finish(partitions[false]),
+			}
+		},
+	)
+}
+
+// ToMap returns a Collector that accumulates the elements into a map[interface{}]interface{}, whose keys and values
+// are the results of applying keyMapper and valueMapper to the elements. The values of the same key are merged with
+// mergeFunction in encounter order, it panics when a key is duplicated if mergeFunction is nil, which is reported as
+// a *PanicError by CollectWith.
+func ToMap(
+	keyMapper func(element interface{}) (key interface{}),
+	valueMapper func(element interface{}) (value interface{}),
+	mergeFunction func(a, b interface{}) interface{},
+) Collector {
+	put := func(m map[interface{}]interface{}, key, value interface{}) {
+		if existing, ok := m[key]; ok {
+			if mergeFunction == nil {
+				panic(fmt.Errorf("duplicate key %v", key))
+			}
+			value = mergeFunction(existing, value)
+		}
+		m[key] = value
+	}
+	return NewCollector(
+		func() interface{} {
+			return make(map[interface{}]interface{})
+		},
+		func(container, element interface{}) interface{} {
+			m := container.(map[interface{}]interface{})
+			put(m, keyMapper(element), valueMapper(element))
+			return m
+		},
+		func(a, b interface{}) interface{} {
+			m := a.(map[interface{}]interface{})
+			for key, value := range b.(map[interface{}]interface{}) {
+				put(m, key, value)
+			}
+			return m
+		},
+		nil,
+	)
+}
+
+// Counting returns a Collector that counts the elements, the result is an int.
+func Counting() Collector {
+	return NewCollector(
+		func() interface{} {
+			return 0
+		},
+		func(container, _ interface{}) interface{} {
+			return container.(int) + 1
+		},
+		func(a, b interface{}) interface{} {
+			return a.(int) + b.(int)
+		},
+		nil,
+	)
+}
+
+// Summing returns a Collector that sums the results of applying mapper to the elements, the result is a float64.
+func Summing(mapper func(element interface{}) float64) Collector {
+	return NewCollector(
+		func() interface{} {
+			return 0.0
+		},
+		func(container, element interface{}) interface{} {
+			return container.(float64) + mapper(element)
+		},
+		func(a, b interface{}) interface{} {
+			return a.(float64) + b.(float64)
+		},
+		nil,
+	)
+}
+
+// Averaging returns a Collector that computes the arithmetic mean of the results of applying mapper to the elements,
+// the result is a float64, or nil if there is no element.
+func Averaging(mapper func(element interface{}) float64) Collector {
+	return NewCollector(
+		func() interface{} {
+			return averaging{}
+		},
+		func(container, element interface{}) interface{} {
+			avg := container.(averaging)
+			return averaging{sum: avg.sum + mapper(element), count: avg.count + 1}
+		},
+		func(a, b interface{}) interface{} {
+			x, y := a.(averaging), b.(averaging)
+			return averaging{sum: x.sum + y.sum, count: x.count + y.count}
+		},
+		func(container interface{}) interface{} {
+			avg := container.(averaging)
+			if avg.count == 0 {
+				return nil
+			}
+			return avg.sum / float64(avg.count)
+		},
+	)
+}
+
+// Joining returns a Collector that concatenates the elements formatted by fmt.Sprint in encounter order, separated by
+// separator. The result is a string.
+func Joining(separator string) Collector {
+	return NewCollector(
+		func() interface{} {
+			return make([]string, 0)
+		},
+		func(container, element interface{}) interface{} {
+			return append(container.([]string), fmt.Sprint(element))
+		},
+		func(a, b interface{}) interface{} {
+			return append(a.([]string), b.([]string)...)
+		},
+		func(container interface{}) interface{} {
+			return strings.Join(container.([]string), separator)
+		},
+	)
+}
+
+func (c *collector) Supply() interface{} {
+	return c.supplier()
+}
+
+func (c *collector) Accumulate(container, element interface{}) interface{} {
+	return c.accumulator(container, element)
+}
+
+func (c *collector) Combine(a, b interface{}) interface{} {
+	return c.combiner(a, b)
+}
+
+func (c *collector) Finish(container interface{}) interface{} {
+	if c.finisher == nil {
+		return container
+	}
+	return c.finisher(container)
+}
+
+func (s *sequentialStream) CollectWith(collector Collector) (result interface{}, err error) {
+	it := s.iterator()
+	err = guard("CollectWith", -1, func() {
+		container := collector.Supply()
+		for index := 0; ; index++ {
+			e, ok := it.next()
+			if !ok {
+				break
+			}
+			container = accumulate(collector, container, e.data, index)
+		}
+		if it.err() == nil {
+			result = collector.Finish(container)
+		}
+	})
+	if err == nil {
+		err = it.err()
+	}
+	s.record(err)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CollectWith accumulates the elements into a container per chunk concurrently, then merges the containers of the
+// adjacent chunks concurrently until a single container is left, so that the encounter order is kept.
+func (p *parallelStream) CollectWith(collector Collector) (result interface{}, err error) {
+	elements, err := p.evaluate()
+	if err != nil {
+		return nil, err
+	}
+	ctx := p.context()
+	chunks := p.parallelism
+	if chunks <= 0 {
+		chunks = DefaultExecutor().Workers() + 1
+	}
+	if chunks > len(elements) {
+		chunks = len(elements)
+	}
+	if chunks <= 1 {
+		return newSequentialStream(p.config, sliceStage(elements)).CollectWith(collector)
+	}
+
+	containers := make([]interface{}, chunks)
+	err = parallelEach(ctx, "CollectWith", p.parallelism, chunks, func(c int) {
+		start, end := c*len(elements)/chunks, (c+1)*len(elements)/chunks
+		container := collector.Supply()
+		for i := start; i < end; i++ {
+			container = accumulate(collector, container, elements[i].data, i)
+		}
+		containers[c] = container
+	})
+	for len(containers) > 1 && err == nil {
+		containers, err = combine(ctx, p.parallelism, collector, containers)
+	}
+	if err == nil {
+		err = guard("CollectWith", -1, func() {
+			result = collector.Finish(containers[0])
+		})
+	}
+	p.record(err)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (e *errStream) CollectWith(Collector) (interface{}, error) {
+	return nil, e.err
+}
+
+// accumulate folds the element at index into container with collector.
+func accumulate(collector Collector, container, element interface{}, index int) interface{} {
+	defer catch("CollectWith", index)
+	return collector.Accumulate(container, element)
+}
+
+// combine merges each pair of the adjacent containers concurrently, the merged containers are returned.
+func combine(ctx context.Context, parallelism int, collector Collector, containers []interface{}) ([]interface{}, error) {
+	merged := make([]interface{}, (len(containers)+1)/2)
+	err := parallelEach(ctx, "CollectWith", parallelism, len(merged), func(i int) {
+		if 2*i+1 < len(containers) {
+			merged[i] = collector.Combine(containers[2*i], containers[2*i+1])
+		} else {
+			merged[i] = containers[2*i]
+		}
+	})
+	return merged, err
+}
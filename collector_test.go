@@ -0,0 +1,183 @@
+package gostream
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestStreamCollectWith(t *testing.T) {
+	isEven := func(element interface{}) (match bool) {
+		return element.(int)%2 == 0
+	}
+	mod3 := func(element interface{}) (key interface{}) {
+		return element.(int) % 3
+	}
+	toFloat64 := func(element interface{}) float64 {
+		return float64(element.(int))
+	}
+	ints := make([]int, 100)
+	for i := range ints {
+		ints[i] = i
+	}
+	tests := []struct {
+		name      string
+		ints      []int
+		collector Collector
+		expect    interface{}
+	}{
+		{"test to slice", []int{3, 1, 2}, ToSlice(), []interface{}{3, 1, 2}},
+		{"test to slice empty", nil, ToSlice(), []interface{}{}},
+		{"test counting", ints, Counting(), 100},
+		{"test counting empty", nil, Counting(), 0},
+		{"test summing", ints, Summing(toFloat64), 4950.0},
+		{"test averaging", ints, Averaging(toFloat64), 49.5},
+		{"test averaging empty", nil, Averaging(toFloat64), nil},
+		{"test joining", []int{1, 2, 3}, Joining(", "), "1, 2, 3"},
+		{"test joining empty", nil, Joining(", "), ""},
+		{
+			"test grouping by",
+			[]int{1, 2, 3, 4, 5, 6, 7},
+			GroupingBy(mod3, nil),
+			map[interface{}]interface{}{0: []interface{}{3, 6}, 1: []interface{}{1, 4, 7}, 2: []interface{}{2, 5}},
+		},
+		{
+			"test grouping by counting",
+			ints,
+			GroupingBy(mod3, Counting()),
+			map[interface{}]interface{}{0: 34, 1: 33, 2: 33},
+		},
+		{
+			"test grouping by nested",
+			[]int{1, 2, 3, 4, 5, 6},
+			GroupingBy(isEvenKey, GroupingBy(mod3, Joining(""))),
+			map[interface{}]interface{}{
+				true:  map[interface{}]interface{}{0: "6", 1: "4", 2: "2"},
+				false: map[interface{}]interface{}{0: "3", 1: "1", 2: "5"},
+			},
+		},
+		{
+			"test partitioning by",
+			[]int{1, 2, 3, 4},
+			PartitioningBy(isEven),
+			map[bool][]interface{}{true: {2, 4}, false: {1, 3}},
+		},
+		{
+			"test partitioning by empty",
+			nil,
+			PartitioningBy(isEven),
+			map[bool][]interface{}{true: {}, false: {}},
+		},
+		{
+			"test partitioning by with",
+			ints,
+			PartitioningByWith(isEven, Summing(toFloat64)),
+			map[bool]interface{}{true: 2450.0, false: 2500.0},
+		},
+		{
+			"test to map",
+			[]int{1, 2, 3},
+			ToMap(func(element interface{}) (key interface{}) {
+				return element
+			}, func(element interface{}) (value interface{}) {
+				return element.(int) * 10
+			}, nil),
+			map[interface{}]interface{}{1: 10, 2: 20, 3: 30},
+		},
+		{
+			"test to map merged in encounter order",
+			ints,
+			ToMap(mod3, func(element interface{}) (value interface{}) {
+				return []int{element.(int)}
+			}, func(a, b interface{}) interface{} {
+				return append(a.([]int), b.([]int)...)
+			}),
+			map[interface{}]interface{}{0: everyThird(ints, 0), 1: everyThird(ints, 1), 2: everyThird(ints, 2)},
+		},
+	}
+	for _, newStream := range []func([]*element) Stream{newSequentialStreamForTest, newParallelStreamForTest} {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := newStream(intSliceToElements(tt.ints)).CollectWith(tt.collector)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expect, result)
+			})
+		}
+	}
+
+	t.Run("test parallelism", func(t *testing.T) {
+		result, err := NewParallelStream(ints).WithParallelism(3).CollectWith(Joining(""))
+		assert.NoError(t, err)
+		expect, err := NewSequentialStream(ints).CollectWith(Joining(""))
+		assert.NoError(t, err)
+		assert.Equal(t, expect, result)
+	})
+
+	t.Run("test custom collector", func(t *testing.T) {
+		product := NewCollector(func() interface{} {
+			return 1
+		}, func(container, element interface{}) interface{} {
+			return container.(int) * element.(int)
+		}, func(a, b interface{}) interface{} {
+			return a.(int) * b.(int)
+		}, func(container interface{}) interface{} {
+			return float64(container.(int))
+		})
+		for _, s := range []Stream{NewSequentialStream([]int{1, 2, 3, 4}), NewParallelStream([]int{1, 2, 3, 4})} {
+			result, err := s.CollectWith(product)
+			assert.NoError(t, err)
+			assert.Equal(t, 24.0, result)
+		}
+	})
+
+	t.Run("test error", func(t *testing.T) {
+		for _, s := range []Stream{testErrStream, testErrStream.Parallel()} {
+			result, err := s.CollectWith(Counting())
+			assert.Error(t, err)
+			assert.Nil(t, result)
+		}
+		failed := errors.New("failed")
+		for _, s := range []Stream{NewSequentialStream(ints), NewParallelStream(ints)} {
+			result, err := s.MapE(func(src interface{}) (dest interface{}, err error) {
+				return nil, failed
+			}).CollectWith(Counting())
+			assert.Equal(t, failed, err)
+			assert.Nil(t, result)
+		}
+	})
+
+	t.Run("test panic", func(t *testing.T) {
+		for _, s := range []Stream{NewSequentialStream(ints), NewParallelStream(ints)} {
+			_, err := s.CollectWith(GroupingBy(func(element interface{}) (key interface{}) {
+				if element.(int) == 42 {
+					panic("boom")
+				}
+				return element
+			}, nil))
+			var p *PanicError
+			if assert.True(t, errors.As(err, &p)) {
+				assert.Equal(t, "CollectWith", p.Op)
+				assert.Equal(t, 42, p.Index)
+				assert.Equal(t, "boom", p.Value)
+			}
+
+			_, err = s.CollectWith(ToMap(mod3, mod3, nil))
+			if assert.True(t, errors.As(err, &p)) {
+				assert.Equal(t, "CollectWith", p.Op)
+				assert.EqualError(t, errors.Unwrap(err), "duplicate key 0")
+			}
+		}
+	})
+}
+
+func isEvenKey(element interface{}) (key interface{}) {
+	return element.(int)%2 == 0
+}
+
+func everyThird(ints []int, start int) []int {
+	result := make([]int, 0)
+	for i := start; i < len(ints); i += 3 {
+		result = append(result, ints[i])
+	}
+	return result
+}
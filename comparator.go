@@ -0,0 +1,104 @@
+package gostream
+
+import "reflect"
+
+// Ordered is a constraint that permits any type supporting the < operator, it's the type of the keys compared by
+// Comparing.
+type Ordered interface {
+	Number | ~string
+}
+
+// Comparator compares a and b, and returns a negative number, zero, or a positive number if a is less than, equal to,
+// or greater than b. Comparators are composed by their methods, for example, the one ordering the widgets by color,
+// and then by weight in descending order is:
+//
+//	Comparing(widgetColor).ThenComparing(Comparing(widgetWeight).Reversed())
+type Comparator[T any] func(a, b T) int
+
+// Comparing returns a Comparator comparing the keys extracted from the values by key, NaN is less than any other key.
+func Comparing[T any, K Ordered](key func(val T) K) Comparator[T] {
+	return func(a, b T) int {
+		return compareOrdered(key(a), key(b))
+	}
+}
+
+// NullsFirst returns a Comparator ordering nil before any other value, and comparing the other values with c, which
+// considers them equal if c is nil. A nil pointer, map, slice, channel, function or interface is taken as nil.
+func NullsFirst[T any](c Comparator[T]) Comparator[T] {
+	return compareNulls(c, -1)
+}
+
+// NullsLast returns a Comparator ordering nil after any other value, and comparing the other values with c, which
+// considers them equal if c is nil. A nil pointer, map, slice, channel, function or interface is taken as nil.
+func NullsLast[T any](c Comparator[T]) Comparator[T] {
+	return compareNulls(c, 1)
+}
+
+// ThenComparing returns a Comparator comparing the values with c, and then with other if they are equal.
+func (c Comparator[T]) ThenComparing(other Comparator[T]) Comparator[T] {
+	return func(a, b T) int {
+		if result := c(a, b); result != 0 {
+			return result
+		}
+		return other(a, b)
+	}
+}
+
+// Reversed returns a Comparator imposing the reverse ordering of c.
+func (c Comparator[T]) Reversed() Comparator[T] {
+	return func(a, b T) int {
+		return c(b, a)
+	}
+}
+
+// Less reports whether a is less than b, it adapts c to the less functions taken by Sorted.
+func (c Comparator[T]) Less(a, b T) bool {
+	return c(a, b) < 0
+}
+
+// compareNulls returns a Comparator ordering nil before any other value if nilOrder is negative, or after if it's
+// positive.
+func compareNulls[T any](c Comparator[T], nilOrder int) Comparator[T] {
+	return func(a, b T) int {
+		aNil, bNil := isNil(a), isNil(b)
+		switch {
+		case aNil && bNil:
+			return 0
+		case aNil:
+			return nilOrder
+		case bNil:
+			return -nilOrder
+		case c == nil:
+			return 0
+		}
+		return c(a, b)
+	}
+}
+
+func compareOrdered[K Ordered](a, b K) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	case a == b:
+		return 0
+	}
+	// at least one of them is NaN
+	aNaN, bNaN := a != a, b != b
+	switch {
+	case aNaN && bNaN:
+		return 0
+	case aNaN:
+		return -1
+	}
+	return 1
+}
+
+func isNil(val interface{}) bool {
+	if val == nil {
+		return true
+	}
+	v := reflect.ValueOf(val)
+	return isNilable(v.Kind()) && v.IsNil()
+}
@@ -0,0 +1,126 @@
+package gostream
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+type comparatorTestWidget struct {
+	color  string
+	weight int
+}
+
+func TestComparator(t *testing.T) {
+	byColor := Comparing(func(w *comparatorTestWidget) string {
+		return w.color
+	})
+	byWeight := Comparing(func(w *comparatorTestWidget) int {
+		return w.weight
+	})
+	red1, red3, green2, blue3 := &comparatorTestWidget{"red", 1}, &comparatorTestWidget{"red", 3},
+		&comparatorTestWidget{"green", 2}, &comparatorTestWidget{"blue", 3}
+	widgets := []*comparatorTestWidget{red1, green2, nil, blue3, red3}
+
+	tests := []struct {
+		name       string
+		comparator Comparator[*comparatorTestWidget]
+		expect     []*comparatorTestWidget
+	}{
+		{
+			"test then comparing",
+			NullsLast(byColor.ThenComparing(byWeight.Reversed())),
+			[]*comparatorTestWidget{blue3, green2, red3, red1, nil},
+		},
+		{
+			"test reversed",
+			NullsFirst(byWeight.Reversed().ThenComparing(byColor)),
+			[]*comparatorTestWidget{nil, blue3, red3, green2, red1},
+		},
+		{
+			"test nil comparator",
+			NullsFirst[*comparatorTestWidget](nil),
+			[]*comparatorTestWidget{nil, red1, green2, blue3, red3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, s := range []TypedStream[*comparatorTestWidget]{NewSequentialTypedStream(widgets),
+				NewParallelTypedStream(widgets)} {
+				res, err := s.SortedBy(tt.comparator).Collect()
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expect, res)
+			}
+		})
+	}
+
+	t.Run("test NaN", func(t *testing.T) {
+		c := Comparing(func(val float64) float64 {
+			return val
+		})
+		assert.Equal(t, 0, c(math.NaN(), math.NaN()))
+		assert.Equal(t, -1, c(math.NaN(), math.Inf(-1)))
+		assert.Equal(t, 1, c(0, math.NaN()))
+		assert.True(t, c.Less(1, 2))
+	})
+}
+
+func TestStreamSortedBy(t *testing.T) {
+	byParity := Comparing(func(val interface{}) int {
+		return val.(int) % 2
+	})
+	for _, newStream := range []func([]*element) Stream{newSequentialStreamForTest, newParallelStreamForTest} {
+		var res []int
+		err := newStream(intSliceToElements([]int{5, 2, 3, 8, 1, 4})).SortedBy(byParity.ThenComparing(
+			Comparing(func(val interface{}) int {
+				return val.(int)
+			}).Reversed())).Collect(&res)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{8, 4, 2, 5, 3, 1}, res)
+
+		err = newStream(intSliceToElements([]int{5, 2, 3, 8, 1, 4})).SortedBy(byParity).Collect(&res)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{2, 8, 4, 5, 3, 1}, res, "the sort should be stable")
+	}
+	assert.Equal(t, testErrStream, testErrStream.SortedBy(byParity))
+}
+
+func TestNumberStreamSortedWith(t *testing.T) {
+	floats := []float64{2, math.NaN(), -1, 3}
+	for _, s := range []Float64Stream{NewSequentialFloat64Stream(floats), NewParallelFloat64Stream(floats),
+		Float64Iterate(0, func(prev float64) float64 {
+			return prev + 1
+		}).Limit(4).Map(func(src float64) (dest float64) {
+			return floats[int(src)]
+		})} {
+		res, err := s.SortedDescending().Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []float64{3, 2, -1}, res[:3])
+		assert.True(t, math.IsNaN(res[3]))
+
+		res, err = s.Filter(func(val float64) bool {
+			return !math.IsNaN(val)
+		}).SortedWith(func(a, b float64) bool {
+			return math.Abs(a) < math.Abs(b)
+		}).Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []float64{-1, 2, 3}, res)
+	}
+
+	ints, err := NewParallelIntStream([]int{13, 21, 12, 22}).SortedWith(func(a, b int) bool {
+		return a%10 < b%10
+	}).Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{21, 12, 22, 13}, ints)
+
+	err = NewSequentialIntStream([]int{1, 2}).SortedWith(func(a, b int) bool {
+		panic("boom")
+	}).Err()
+	var p *PanicError
+	if assert.True(t, errors.As(err, &p)) {
+		assert.Equal(t, "SortedWith", p.Op)
+	}
+	assert.Error(t, testErrIntStream.SortedDescending().Err())
+	assert.Error(t, testErrIntStream.SortedWith(nil).Err())
+}
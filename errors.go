@@ -0,0 +1,176 @@
+package gostream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrorMode decides how a stream handles the errors returned by the functions supplied to MapE, FilterE and FlatMapE.
+type ErrorMode int
+
+const (
+	// StopOnFirstError stops the stream at the first error returned, which is reported as is. It's the default mode.
+	StopOnFirstError ErrorMode = iota
+	// CollectAllErrors applies the function to all the elements even if some of them failed, and reports all the
+	// errors returned as a *MultiError in encounter order.
+	CollectAllErrors
+)
+
+// MultiError is the error reporting all the errors returned by the functions supplied to a stream in
+// CollectAllErrors mode.
+type MultiError struct {
+	Errors []error
+	// stopped is true if the last error stopped the stream after the other errors were collected, such as a panic or
+	// the error of the context.
+	stopped bool
+}
+
+// errorSink gathers the errors returned by the function supplied to a lazy stage according to the error mode.
+type errorSink struct {
+	mode ErrorMode
+	errs []error
+}
+
+func (m *MultiError) Error() string {
+	messages := make([]string, 0, len(m.Errors))
+	for _, err := range m.Errors {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("%d errors occurred: %s", len(m.Errors), strings.Join(messages, "; "))
+}
+
+// Is reports whether any of the errors matches target.
+func (m *MultiError) Is(target error) bool {
+	for _, err := range m.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error that matches target, and if so, sets target to that error value and returns true.
+func (m *MultiError) As(target interface{}) bool {
+	for _, err := range m.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkErrorMode returns an error if mode is unknown.
+func checkErrorMode(mode ErrorMode) error {
+	if mode != StopOnFirstError && mode != CollectAllErrors {
+		return fmt.Errorf("error mode error, unknown mode: %d", mode)
+	}
+	return nil
+}
+
+// withErrorMode returns a copy of the config whose error mode is mode.
+func (c config) withErrorMode(mode ErrorMode) config {
+	c.errorMode = mode
+	return c
+}
+
+// eachE calls f with each index in [0, n), concurrently on the default executor if parallel is true, and gathers the
+// errors returned by f according to the error mode. The panic of f is reported as the *PanicError of op, which takes
+// precedence over the errors returned, and the error of the context is returned if it's done.
+func (c config) eachE(op string, parallel bool, n int, f func(i int) error) error {
+	return c.eachEAt(op, parallel, 0, n, f)
+}
+
+// eachEAt is the eachE of the batch of elements starting at offset of the stream, offset is added to the index
+// reported by the *PanicError.
+func (c config) eachEAt(op string, parallel bool, offset, n int, f func(i int) error) error {
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	stopCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	errs := make([]error, n)
+	var once sync.Once
+	failed := false
+	run := func(i int) {
+		if errs[i] = f(i); errs[i] != nil {
+			once.Do(func() {
+				failed = true
+				if c.errorMode == StopOnFirstError {
+					stop()
+				}
+			})
+		}
+	}
+	var err error
+	if parallel {
+		err = parallelEachAt(stopCtx, op, c.parallelism, offset, n, run)
+	} else {
+		err = sequentialEachAt(stopCtx, op, offset, n, run)
+	}
+	if _, ok := err.(*PanicError); ok {
+		return err
+	}
+	if failed {
+		sink := errorSink{mode: c.errorMode}
+		for _, e := range errs {
+			if e != nil {
+				sink.add(e)
+			}
+		}
+		return sink.result(nil)
+	}
+	if err != nil {
+		return ctxErr(ctx)
+	}
+	return nil
+}
+
+// add records err, it reports whether the stage should stop.
+func (s *errorSink) add(err error) (stop bool) {
+	s.errs = append(s.errs, err)
+	return s.mode == StopOnFirstError
+}
+
+// result returns the error the stage should report. upstream is the error of the upstream stage, the errors
+// collected by the upstream stages are merged with the errors of this stage.
+func (s *errorSink) result(upstream error) error {
+	switch {
+	case len(s.errs) == 0:
+		return upstream
+	case s.mode == StopOnFirstError:
+		return mergeErrors(upstream, s.errs[0])
+	default:
+		return mergeErrors(upstream, &MultiError{Errors: s.errs})
+	}
+}
+
+// mergeErrors returns the error of a stage, given the error of its upstream stages and the error occurred in itself.
+// The errors collected by the upstream stages are merged with the errors of the stage, so that none of them is lost
+// if the stage failed with any other error, which stops the stream. Any other upstream error stopped the stream before
+// the stage failed, so it's returned as is.
+func mergeErrors(upstream, err error) error {
+	collected, ok := upstream.(*MultiError)
+	switch {
+	case upstream == nil:
+		return err
+	case !ok || collected.stopped || err == nil:
+		return upstream
+	}
+	errs := make([]error, 0, len(collected.Errors)+1)
+	errs = append(errs, collected.Errors...)
+	if m, ok := err.(*MultiError); ok {
+		return &MultiError{Errors: append(errs, m.Errors...), stopped: m.stopped}
+	}
+	return &MultiError{Errors: append(errs, err), stopped: true}
+}
+
+// isCollected reports whether err only reports the errors collected in CollectAllErrors mode, which don't stop the
+// stream.
+func isCollected(err error) bool {
+	m, ok := err.(*MultiError)
+	return ok && !m.stopped
+}
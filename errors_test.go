@@ -0,0 +1,181 @@
+package gostream
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var errOdd = errors.New("odd")
+
+func failOdd(i int) error {
+	if i%2 == 1 {
+		return fmt.Errorf("element %d: %w", i, errOdd)
+	}
+	return nil
+}
+
+func TestMultiError(t *testing.T) {
+	m := &MultiError{Errors: []error{errors.New("a"), &PanicError{Op: "Map", Value: "b"}}}
+	assert.Equal(t, "2 errors occurred: a; panic in Map at element 0: b\n", m.Error())
+	assert.True(t, errors.Is(m, m.Errors[0]))
+	assert.False(t, errors.Is(m, errOdd))
+	var p *PanicError
+	assert.True(t, errors.As(m, &p))
+	assert.Equal(t, "Map", p.Op)
+}
+
+func TestStreamE(t *testing.T) {
+	for _, newStream := range []func([]*element) Stream{newSequentialStreamForTest, newParallelStreamForTest} {
+		ints := intSliceToElements([]int{0, 1, 2, 3, 4})
+		mapE := func(src interface{}) (dest interface{}, err error) {
+			return src.(int) * 10, failOdd(src.(int))
+		}
+		filterE := func(val interface{}) (match bool, err error) {
+			return val.(int) > 0, failOdd(val.(int))
+		}
+		flatMapE := func(val interface{}) (Stream, error) {
+			return NewSequentialStream([]int{val.(int), val.(int)}), failOdd(val.(int))
+		}
+
+		t.Run("test no error", func(t *testing.T) {
+			var res []int
+			assert.NoError(t, newStream(intSliceToElements([]int{0, 2})).MapE(mapE).Collect(&res))
+			assertSliceEquals(t, []int{0, 20}, res)
+			assert.NoError(t, newStream(intSliceToElements([]int{0, 2})).FilterE(filterE).Collect(&res))
+			assertSliceEquals(t, []int{2}, res)
+			assert.NoError(t, newStream(intSliceToElements([]int{0, 2})).FlatMapE(flatMapE).Collect(&res))
+			assertSliceEquals(t, []int{0, 0, 2, 2}, res)
+		})
+
+		t.Run("test stop on first error", func(t *testing.T) {
+			for _, s := range []Stream{newStream(ints).MapE(mapE), newStream(ints).FilterE(filterE), newStream(ints).FlatMapE(flatMapE)} {
+				var res []int
+				err := s.Collect(&res)
+				assert.EqualError(t, err, "element 1: odd")
+				assert.True(t, errors.Is(s.Err(), errOdd))
+			}
+		})
+
+		t.Run("test collect all errors", func(t *testing.T) {
+			for _, s := range []Stream{
+				newStream(ints).WithErrorMode(CollectAllErrors).MapE(mapE),
+				newStream(ints).WithErrorMode(CollectAllErrors).FilterE(filterE),
+				newStream(ints).WithErrorMode(CollectAllErrors).FlatMapE(flatMapE),
+			} {
+				var res []int
+				err := s.Collect(&res)
+				var m *MultiError
+				if assert.True(t, errors.As(err, &m)) {
+					assert.Len(t, m.Errors, 2)
+					assert.EqualError(t, m.Errors[0], "element 1: odd")
+					assert.EqualError(t, m.Errors[1], "element 3: odd")
+				}
+			}
+		})
+
+		t.Run("test merge errors of stages", func(t *testing.T) {
+			s := newStream(ints).WithErrorMode(CollectAllErrors).MapE(mapE).MapE(func(src interface{}) (dest interface{}, err error) {
+				return src, fmt.Errorf("failed %v", src)
+			})
+			var res []int
+			var m *MultiError
+			if assert.True(t, errors.As(s.Collect(&res), &m)) {
+				assert.Len(t, m.Errors, 5)
+			}
+		})
+
+		t.Run("test collected errors followed by a panic", func(t *testing.T) {
+			s := newStream(ints).WithErrorMode(CollectAllErrors).MapE(mapE).Map(func(src interface{}) (dest interface{}) {
+				if src.(int) == 40 {
+					panic("boom")
+				}
+				return src
+			})
+			var res []int
+			err := s.Collect(&res)
+			var m *MultiError
+			if assert.True(t, errors.As(err, &m)) {
+				assert.Len(t, m.Errors, 3)
+				assert.EqualError(t, m.Errors[0], "element 1: odd")
+				assert.EqualError(t, m.Errors[1], "element 3: odd")
+			}
+			var p *PanicError
+			if assert.True(t, errors.As(err, &p)) {
+				assert.Equal(t, "Map", p.Op)
+			}
+			assert.True(t, errors.Is(s.Err(), errOdd))
+		})
+
+		t.Run("test unknown mode", func(t *testing.T) {
+			assert.Error(t, newStream(ints).WithErrorMode(ErrorMode(-1)).Err())
+			assert.Same(t, testErrStream, testErrStream.WithErrorMode(CollectAllErrors).MapE(mapE).FilterE(filterE).FlatMapE(flatMapE))
+		})
+	}
+}
+
+func TestIntStreamE(t *testing.T) {
+	mapE := func(src int) (dest int, err error) {
+		return src * 10, failOdd(src)
+	}
+	filterE := func(val int) (match bool, err error) {
+		return val > 0, failOdd(val)
+	}
+	flatMapE := func(val int) (IntStream, error) {
+		return NewSequentialIntStream([]int{val, val}), failOdd(val)
+	}
+	for _, s := range []IntStream{NewSequentialIntStream([]int{0, 1, 2, 3}), NewParallelIntStream([]int{0, 1, 2, 3})} {
+		for _, res := range []IntStream{s.MapE(mapE), s.FilterE(filterE), s.FlatMapE(flatMapE)} {
+			assert.EqualError(t, res.Err(), "element 1: odd")
+			assert.Equal(t, s.IsParallel(), res.IsParallel())
+		}
+		for _, res := range []IntStream{s.WithErrorMode(CollectAllErrors).MapE(mapE), s.WithErrorMode(CollectAllErrors).FilterE(filterE)} {
+			var m *MultiError
+			if assert.True(t, errors.As(res.Err(), &m)) {
+				assert.Len(t, m.Errors, 2)
+			}
+		}
+
+		even := s.Filter(func(val int) (match bool) {
+			return val%2 == 0
+		})
+		res, err := even.MapE(mapE).Collect()
+		assert.NoError(t, err)
+		assertSliceEquals(t, []int{0, 20}, res)
+		res, err = even.FilterE(filterE).Collect()
+		assert.NoError(t, err)
+		assertSliceEquals(t, []int{2}, res)
+		res, err = even.FlatMapE(flatMapE).Collect()
+		assert.NoError(t, err)
+		assertSliceEquals(t, []int{0, 0, 2, 2}, res)
+		assert.Error(t, s.WithErrorMode(ErrorMode(2)).Err())
+	}
+	assert.Same(t, testErrIntStream, testErrIntStream.WithErrorMode(CollectAllErrors).MapE(mapE).FilterE(filterE).FlatMapE(flatMapE))
+}
+
+func TestFloat64StreamE(t *testing.T) {
+	mapE := func(src float64) (dest float64, err error) {
+		return src * 10, failOdd(int(src))
+	}
+	filterE := func(val float64) (match bool, err error) {
+		return val > 0, failOdd(int(val))
+	}
+	flatMapE := func(val float64) (Float64Stream, error) {
+		return NewSequentialFloat64Stream([]float64{val, val}), failOdd(int(val))
+	}
+	for _, s := range []Float64Stream{NewSequentialFloat64Stream([]float64{0, 1, 2, 3}), NewParallelFloat64Stream([]float64{0, 1, 2, 3})} {
+		for _, res := range []Float64Stream{s.MapE(mapE), s.FilterE(filterE), s.FlatMapE(flatMapE)} {
+			assert.EqualError(t, res.Err(), "element 1: odd")
+		}
+		var m *MultiError
+		if assert.True(t, errors.As(s.WithErrorMode(CollectAllErrors).FlatMapE(flatMapE).Err(), &m)) {
+			assert.Len(t, m.Errors, 2)
+		}
+
+		res, err := s.Limit(1).MapE(mapE).Collect()
+		assert.NoError(t, err)
+		assertFloat64SliceEquals(t, []float64{0}, res)
+	}
+	assert.Same(t, testSerialErrFloat64Stream, testSerialErrFloat64Stream.WithErrorMode(CollectAllErrors).MapE(mapE).FilterE(filterE).FlatMapE(flatMapE))
+}
@@ -0,0 +1,294 @@
+package gostream
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// chunksPerWorker is the number of chunks each participant of a parallel operation is expected to run, more chunks
+// than participants balance the load when the costs of the elements are uneven.
+const chunksPerWorker = 4
+
+var (
+	defaultExecutor atomic.Value
+	// defaultExecutorMu serializes the replacements of the default executor.
+	defaultExecutorMu sync.Mutex
+)
+
+// Executor runs the work of parallel streams on a bounded set of worker goroutines.
+// The work of an operation is split into chunks, which are run by the calling goroutine and the idle workers, so the
+// number of goroutines never grows with the number of elements.
+type Executor struct {
+	workers int
+	tasks   chan func()
+	done    chan struct{}
+	start   sync.Once
+	stop    sync.Once
+}
+
+// config holds the settings of a stream, which are inherited by the streams derived from it.
+type config struct {
+	// ctx is the context the operations are cancelled with, nil means never cancelled.
+	ctx context.Context
+	// parallelism is the maximum number of goroutines running a parallel operation, including the calling goroutine.
+	// The number of workers of the default executor plus one is used if it's not positive.
+	parallelism int
+	// errorMode decides how the errors returned by the functions supplied to MapE, FilterE and FlatMapE are handled.
+	errorMode ErrorMode
+}
+
+func init() {
+	defaultExecutor.Store(NewExecutor(0))
+}
+
+// NewExecutor returns an executor running the work with the specified number of worker goroutines,
+// runtime.GOMAXPROCS(0) workers are used if workers is not positive.
+// The workers are started on the first use of the executor.
+func NewExecutor(workers int) *Executor {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return &Executor{
+		workers: workers,
+		tasks:   make(chan func()),
+		done:    make(chan struct{}),
+	}
+}
+
+// DefaultExecutor returns the executor shared by all the parallel streams.
+func DefaultExecutor() *Executor {
+	return defaultExecutor.Load().(*Executor)
+}
+
+// SetDefaultExecutor replaces the executor shared by all the parallel streams, the previous executor is returned and
+// it's not closed.
+func SetDefaultExecutor(e *Executor) (previous *Executor) {
+	defaultExecutorMu.Lock()
+	defer defaultExecutorMu.Unlock()
+	previous = DefaultExecutor()
+	defaultExecutor.Store(e)
+	return previous
+}
+
+// Workers returns the number of worker goroutines of the executor.
+func (e *Executor) Workers() int {
+	return e.workers
+}
+
+// Close stops the worker goroutines of the executor. The work submitted after closing is run by the calling
+// goroutines.
+func (e *Executor) Close() {
+	e.stop.Do(func() {
+		close(e.done)
+	})
+}
+
+func (e *Executor) work() {
+	for {
+		select {
+		case task := <-e.tasks:
+			task()
+		case <-e.done:
+			return
+		}
+	}
+}
+
+// trySubmit hands task to an idle worker, false is returned if all the workers are busy.
+func (e *Executor) trySubmit(task func()) bool {
+	e.start.Do(func() {
+		for i := 0; i < e.workers; i++ {
+			go e.work()
+		}
+	})
+	select {
+	case e.tasks <- task:
+		return true
+	default:
+		return false
+	}
+}
+
+// forEach calls f with each index in [0, n) and waits for all of them to return. The indexes are split into chunks,
+// which are run by the calling goroutine and at most parallelism-1 idle workers.
+// No more chunk is run once ctx is done, and the error of ctx is returned.
+func (e *Executor) forEach(ctx context.Context, parallelism, n int, f func(i int)) error {
+	if parallelism <= 0 {
+		parallelism = e.workers + 1
+	}
+	if parallelism > n {
+		parallelism = n
+	}
+	if parallelism <= 1 {
+		for i := 0; i < n && ctxErr(ctx) == nil; i++ {
+			f(i)
+		}
+		return ctxErr(ctx)
+	}
+
+	chunkSize := (n + parallelism*chunksPerWorker - 1) / (parallelism * chunksPerWorker)
+	var next int64
+	run := func() {
+		for ctxErr(ctx) == nil {
+			end := int(atomic.AddInt64(&next, int64(chunkSize)))
+			start := end - chunkSize
+			if start >= n {
+				return
+			}
+			if end > n {
+				end = n
+			}
+			for i := start; i < end; i++ {
+				f(i)
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 1; i < parallelism; i++ {
+		wg.Add(1)
+		if !e.trySubmit(func() {
+			defer wg.Done()
+			run()
+		}) {
+			// all the workers are busy, the remaining chunks are run by the participants already started
+			wg.Done()
+			break
+		}
+	}
+	run()
+	wg.Wait()
+	return ctxErr(ctx)
+}
+
+// parallelEach calls f with each index in [0, n) concurrently on the default executor and waits for all of them to
+// return. No more call is scheduled once ctx is done or f panics, the error of ctx or the *PanicError of op is
+// returned.
+func parallelEach(ctx context.Context, op string, parallelism, n int, f func(i int)) error {
+	return parallelEachAt(ctx, op, parallelism, 0, n, f)
+}
+
+// parallelEachAt is the parallelEach of the batch of elements starting at offset of the stream, offset is added to
+// the index reported by the *PanicError.
+func parallelEachAt(ctx context.Context, op string, parallelism, offset, n int, f func(i int)) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	stopCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	var once sync.Once
+	var panicErr error
+	err := DefaultExecutor().forEach(stopCtx, parallelism, n, func(i int) {
+		if err := guard(op, offset+i, func() { f(i) }); err != nil {
+			once.Do(func() {
+				panicErr = err
+				stop()
+			})
+		}
+	})
+	if panicErr != nil {
+		return panicErr
+	}
+	if err != nil {
+		return ctxErr(ctx)
+	}
+	return nil
+}
+
+// maxBatchSize is the maximum number of elements pulled together by a parallel operation which pulls its upstream in
+// batches, so that an infinite stream is processed in bounded memory.
+const maxBatchSize = 1 << 14
+
+// firstBatchSize returns the number of elements of the first batch pulled by a parallel operation running on
+// parallelism goroutines, the following batches grow by nextBatchSize.
+func firstBatchSize(parallelism int) int {
+	return batchSize(parallelism) * chunksPerWorker
+}
+
+// nextBatchSize doubles the size of the batches up to maxBatchSize, so that a finite stream is pulled in a few
+// batches.
+func nextBatchSize(size int) int {
+	if size >= maxBatchSize/2 {
+		return maxBatchSize
+	}
+	return size * 2
+}
+
+// pullBatch pulls at most n elements of it.
+func pullBatch(it iterator, n int) []*element {
+	batch := make([]*element, 0, n)
+	for len(batch) < n {
+		e, ok := it.next()
+		if !ok {
+			break
+		}
+		batch = append(batch, e)
+	}
+	return batch
+}
+
+// batchSize returns the number of elements tested together by the short-circuiting operations running on parallelism
+// goroutines, a non-positive parallelism means the default one.
+func batchSize(parallelism int) int {
+	if parallelism <= 0 {
+		return DefaultExecutor().Workers() + 1
+	}
+	return parallelism
+}
+
+// matchingPrefix returns the number of the leading items matching predicate. The items are tested in order in batches
+// of batchSize(parallelism) items, the items of a batch are tested concurrently, and no more batch is tested once an
+// item doesn't match. offset is added to the index of the item reported when predicate panics.
+func matchingPrefix[T any](ctx context.Context, op string, parallelism, offset int, items []T,
+	predicate func(item T) bool) (int, error) {
+	size := batchSize(parallelism)
+	if size == 1 {
+		for i := range items {
+			if err := ctxErr(ctx); err != nil {
+				return 0, err
+			}
+			var match bool
+			if err := guard(op, offset+i, func() { match = predicate(items[i]) }); err != nil {
+				return 0, err
+			}
+			if !match {
+				return i, nil
+			}
+		}
+		return len(items), nil
+	}
+	matches := make([]bool, len(items))
+	for start := 0; start < len(items); start += size {
+		end := start + size
+		if end > len(items) {
+			end = len(items)
+		}
+		err := parallelEach(ctx, op, parallelism, end-start, func(i int) {
+			defer catch(op, offset+start+i)
+			matches[start+i] = predicate(items[start+i])
+		})
+		if err != nil {
+			return 0, err
+		}
+		for i := start; i < end; i++ {
+			if !matches[i] {
+				return i, nil
+			}
+		}
+	}
+	return len(items), nil
+}
+
+// withContext returns a copy of the config whose context is ctx.
+func (c config) withContext(ctx context.Context) config {
+	c.ctx = ctx
+	return c
+}
+
+// withParallelism returns a copy of the config whose parallelism is n.
+func (c config) withParallelism(n int) config {
+	c.parallelism = n
+	return c
+}
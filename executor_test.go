@@ -0,0 +1,76 @@
+package gostream
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+)
+
+func TestNewExecutor(t *testing.T) {
+	e := NewExecutor(3)
+	defer e.Close()
+	assert.Equal(t, 3, e.Workers())
+	assert.True(t, NewExecutor(0).Workers() > 0)
+}
+
+func TestExecutor_forEach(t *testing.T) {
+	e := NewExecutor(4)
+	defer e.Close()
+	for _, parallelism := range []int{0, 1, 2, 8} {
+		for _, n := range []int{0, 1, 7, 1000} {
+			counts := make([]int32, n)
+			var running, maxRunning int32
+			assert.NoError(t, e.forEach(nil, parallelism, n, func(i int) {
+				r := atomic.AddInt32(&running, 1)
+				for m := atomic.LoadInt32(&maxRunning); r > m && !atomic.CompareAndSwapInt32(&maxRunning, m, r); {
+					m = atomic.LoadInt32(&maxRunning)
+				}
+				atomic.AddInt32(&counts[i], 1)
+				atomic.AddInt32(&running, -1)
+			}))
+			for i := range counts {
+				assert.Equal(t, int32(1), counts[i])
+			}
+			if parallelism > 0 {
+				assert.True(t, int(maxRunning) <= parallelism)
+			}
+		}
+	}
+
+	t.Run("test cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var calls int32
+		err := e.forEach(ctx, 2, 100, func(int) {
+			atomic.AddInt32(&calls, 1)
+		})
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, int32(0), calls)
+	})
+
+	t.Run("test closed", func(t *testing.T) {
+		closed := NewExecutor(2)
+		closed.Close()
+		var calls int32
+		assert.NoError(t, closed.forEach(nil, 3, 10, func(int) {
+			atomic.AddInt32(&calls, 1)
+		}))
+		assert.Equal(t, int32(10), calls)
+	})
+}
+
+func TestSetDefaultExecutor(t *testing.T) {
+	e := NewExecutor(2)
+	previous := SetDefaultExecutor(e)
+	defer func() {
+		SetDefaultExecutor(previous)
+		e.Close()
+	}()
+	assert.Same(t, e, DefaultExecutor())
+	var res []int
+	assert.NoError(t, NewParallelStream([]int{1, 2, 3}).Map(func(src interface{}) (dest interface{}) {
+		return src.(int) + 1
+	}).Collect(&res))
+	assertSliceEquals(t, []int{2, 3, 4}, res)
+}
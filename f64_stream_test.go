@@ -1,6 +1,7 @@
 package gostream
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/stretchr/testify/assert"
@@ -12,6 +13,15 @@ var (
 	testParallelErrFloat64Stream = &errFloat64Stream{err: errors.New(""), parallel: true}
 )
 
+func TestZipFloat64(t *testing.T) {
+	res, err := ZipFloat64(NewSequentialFloat64Stream([]float64{1, 2}), NewSequentialFloat64Stream([]float64{0.5, 0.25, 4}),
+		func(first, second float64) float64 {
+			return first * second
+		}).Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0.5, 0.5}, res)
+}
+
 func Test_errFloat64Stream_Average(t *testing.T) {
 	t.Run("test serial", func(t *testing.T) {
 		ans, err := testSerialErrFloat64Stream.Average()
@@ -1003,3 +1013,30 @@ func TestNewParallelFloat64Stream(t *testing.T) {
 		})
 	}
 }
+
+func TestFloat64StreamWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, s := range []Float64Stream{NewSequentialFloat64Stream([]float64{1, 2}), NewParallelFloat64Stream([]float64{1, 2})} {
+		s = s.WithContext(ctx).Filter(func(val float64) (match bool) {
+			cancel()
+			return true
+		})
+		assert.Equal(t, context.Canceled, s.Err())
+		_, err := s.Sum()
+		assert.Equal(t, context.Canceled, err)
+	}
+	assert.Same(t, testSerialErrFloat64Stream, testSerialErrFloat64Stream.WithContext(ctx))
+}
+
+func TestFloat64StreamWithParallelism(t *testing.T) {
+	for _, s := range []Float64Stream{NewSequentialFloat64Stream([]float64{1, 2, 3}), NewParallelFloat64Stream([]float64{1, 2, 3})} {
+		res, err := s.WithParallelism(2).Map(func(src float64) (dest float64) {
+			return src * 2
+		}).Collect()
+		assert.NoError(t, err)
+		assertFloat64SliceEquals(t, []float64{2, 4, 6}, res)
+		assert.Error(t, s.WithParallelism(-1).Err())
+	}
+	assert.Same(t, testSerialErrFloat64Stream, testSerialErrFloat64Stream.WithParallelism(2))
+}
@@ -0,0 +1,133 @@
+package gostream
+
+import "context"
+
+func (s *sequentialStream) ForEach(action func(val interface{})) error {
+	return s.forEach("ForEach", action)
+}
+
+func (s *sequentialStream) ForEachOrdered(action func(val interface{})) error {
+	return s.forEach("ForEachOrdered", action)
+}
+
+// forEach pulls the elements one at a time and performs action on each of them.
+func (s *sequentialStream) forEach(op string, action func(val interface{})) error {
+	it := s.iterator()
+	index := 0
+	err := guardAt(op, &index, func() {
+		for e, ok := it.next(); ok; e, ok = it.next() {
+			action(e.data)
+			index++
+		}
+	})
+	if err == nil {
+		err = it.err()
+	}
+	s.record(err)
+	return err
+}
+
+func (s *sequentialStream) Peek(action func(val interface{})) Stream {
+	if s.tail == nil {
+		return s
+	}
+	return newSequentialStream(s.config, peekStage(s.tail, action))
+}
+
+func (p *parallelStream) ForEach(action func(val interface{})) error {
+	elements, err := p.evaluate()
+	if err != nil {
+		return err
+	}
+	err = parallelEach(p.ctx, "ForEach", p.parallelism, len(elements), func(i int) {
+		action(elements[i].data)
+	})
+	p.record(err)
+	return err
+}
+
+func (p *parallelStream) ForEachOrdered(action func(val interface{})) error {
+	elements, err := p.evaluate()
+	if err != nil {
+		return err
+	}
+	err = sequentialEach(p.ctx, "ForEachOrdered", len(elements), func(i int) {
+		action(elements[i].data)
+	})
+	p.record(err)
+	return err
+}
+
+func (p *parallelStream) Peek(action func(val interface{})) Stream {
+	if p.tail == nil {
+		return p
+	}
+	return newParallelStream(p.config, batchStage(p.tail, p.parallelism, func(ctx context.Context, offset int,
+		elements []*element) ([]*element, error) {
+		err := parallelEachAt(ctx, "Peek", p.parallelism, offset, len(elements), func(i int) {
+			action(elements[i].data)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return elements, nil
+	}))
+}
+
+func (e *errStream) ForEach(func(val interface{})) error {
+	return e.err
+}
+
+func (e *errStream) ForEachOrdered(func(val interface{})) error {
+	return e.err
+}
+
+func (e *errStream) Peek(func(val interface{})) Stream {
+	return e
+}
+
+func (s *sequentialNumberStream[N]) ForEach(action func(val N)) error {
+	return sequentialEach(s.ctx, "ForEach", len(s.elements), func(i int) {
+		action(s.elements[i])
+	})
+}
+
+func (s *sequentialNumberStream[N]) ForEachOrdered(action func(val N)) error {
+	return sequentialEach(s.ctx, "ForEachOrdered", len(s.elements), func(i int) {
+		action(s.elements[i])
+	})
+}
+
+// Peek of a NumberStream is lazy, so the elements are pulled through the Peek of the pipeline, and action is performed
+// once they are consumed.
+func (s *sequentialNumberStream[N]) Peek(action func(val N)) NumberStream[N] {
+	return newLazyNumberStream[N](untypedNumberStream[N](s).Peek(untypedAction(action)))
+}
+
+func (p *parallelNumberStream[N]) ForEach(action func(val N)) error {
+	return parallelEach(p.ctx, "ForEach", p.parallelism, len(p.elements), func(i int) {
+		action(p.elements[i])
+	})
+}
+
+func (p *parallelNumberStream[N]) ForEachOrdered(action func(val N)) error {
+	return sequentialEach(p.ctx, "ForEachOrdered", len(p.elements), func(i int) {
+		action(p.elements[i])
+	})
+}
+
+func (p *parallelNumberStream[N]) Peek(action func(val N)) NumberStream[N] {
+	return newLazyNumberStream[N](untypedNumberStream[N](p).Peek(untypedAction(action)))
+}
+
+func (e *errNumberStream[N]) ForEach(func(val N)) error {
+	return e.err
+}
+
+func (e *errNumberStream[N]) ForEachOrdered(func(val N)) error {
+	return e.err
+}
+
+func (e *errNumberStream[N]) Peek(func(val N)) NumberStream[N] {
+	return e
+}
@@ -0,0 +1,188 @@
+package gostream
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestStreamForEach(t *testing.T) {
+	ints := make([]int, 1000)
+	for i := range ints {
+		ints[i] = i
+	}
+	for _, newStream := range []func([]*element) Stream{newSequentialStreamForTest, newParallelStreamForTest} {
+		t.Run("test for each", func(t *testing.T) {
+			var mu sync.Mutex
+			visited := make([]int, 0)
+			err := newStream(intSliceToElements(ints)).ForEach(func(val interface{}) {
+				mu.Lock()
+				defer mu.Unlock()
+				visited = append(visited, val.(int))
+			})
+			assert.NoError(t, err)
+			sort.Ints(visited)
+			assert.Equal(t, ints, visited)
+		})
+
+		t.Run("test for each ordered", func(t *testing.T) {
+			visited := make([]int, 0)
+			err := newStream(intSliceToElements(ints)).Map(func(src interface{}) (dest interface{}) {
+				return src.(int) * 2
+			}).ForEachOrdered(func(val interface{}) {
+				visited = append(visited, val.(int)/2)
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, ints, visited)
+		})
+
+		t.Run("test empty", func(t *testing.T) {
+			err := newStream(nil).ForEach(func(val interface{}) {
+				t.Fatal("action called on an empty stream")
+			})
+			assert.NoError(t, err)
+		})
+
+		t.Run("test panic", func(t *testing.T) {
+			for _, s := range []Stream{newStream(intSliceToElements(ints)), newStream(intSliceToElements(ints)).WithParallelism(1)} {
+				err := s.ForEachOrdered(func(val interface{}) {
+					if val.(int) == 42 {
+						panic("boom")
+					}
+				})
+				var p *PanicError
+				if assert.True(t, errors.As(err, &p)) {
+					assert.Equal(t, "ForEachOrdered", p.Op)
+					assert.Equal(t, 42, p.Index)
+				}
+				assert.Equal(t, err, s.Err())
+			}
+		})
+	}
+
+	for _, s := range []Stream{testErrStream, testErrStream.Parallel()} {
+		assert.Error(t, s.ForEach(func(val interface{}) {}))
+		assert.Error(t, s.ForEachOrdered(func(val interface{}) {}))
+		assert.Equal(t, s, s.Peek(func(val interface{}) {}))
+	}
+}
+
+func TestStreamPeek(t *testing.T) {
+	t.Run("test lazy", func(t *testing.T) {
+		peeked := make([]int, 0)
+		s := NewSequentialStream([]int{1, 2, 3, 4, 5}).Peek(func(val interface{}) {
+			peeked = append(peeked, val.(int))
+		})
+		assert.Empty(t, peeked)
+		first, err := s.Limit(2).FindFirst()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, first)
+		assert.Equal(t, []int{1}, peeked)
+	})
+
+	t.Run("test parallel", func(t *testing.T) {
+		var mu sync.Mutex
+		peeked := make([]int, 0)
+		var res []int
+		err := NewParallelStream([]int{3, 1, 2}).Peek(func(val interface{}) {
+			mu.Lock()
+			defer mu.Unlock()
+			peeked = append(peeked, val.(int))
+		}).Collect(&res)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{3, 1, 2}, res)
+		assert.ElementsMatch(t, []int{3, 1, 2}, peeked)
+	})
+
+	t.Run("test panic", func(t *testing.T) {
+		for _, s := range []Stream{NewSequentialStream([]int{1, 2, 3}), NewParallelStream([]int{1, 2, 3})} {
+			err := s.Peek(func(val interface{}) {
+				if val.(int) == 2 {
+					panic("boom")
+				}
+			}).ForEach(func(val interface{}) {})
+			var p *PanicError
+			if assert.True(t, errors.As(err, &p)) {
+				assert.Equal(t, "Peek", p.Op)
+				assert.Equal(t, 1, p.Index)
+			}
+		}
+	})
+}
+
+func TestNumberStreamForEach(t *testing.T) {
+	for _, s := range []IntStream{NewSequentialIntStream([]int{1, 2, 3}), NewParallelIntStream([]int{1, 2, 3}),
+		IntRangeClosed(1, 3)} {
+		var mu sync.Mutex
+		sum := 0
+		err := s.ForEach(func(val int) {
+			mu.Lock()
+			defer mu.Unlock()
+			sum += val
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 6, sum)
+
+		visited := make([]int, 0)
+		err = s.ForEachOrdered(func(val int) {
+			visited = append(visited, val)
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, visited)
+
+		peeked := make([]int, 0)
+		res, err := s.Sequential().Peek(func(val int) {
+			peeked = append(peeked, val)
+		}).Map(func(src int) (dest int) {
+			return src * 10
+		}).Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []int{10, 20, 30}, res)
+		assert.Equal(t, []int{1, 2, 3}, peeked)
+	}
+
+	t.Run("test lazy", func(t *testing.T) {
+		for _, s := range []IntStream{NewSequentialIntStream([]int{1, 2, 3}), NewParallelIntStream([]int{1, 2, 3})} {
+			var peeked int32
+			s = s.Peek(func(val int) {
+				atomic.AddInt32(&peeked, 1)
+			})
+			assert.Equal(t, int32(0), atomic.LoadInt32(&peeked))
+			sum, err := s.Sum()
+			assert.NoError(t, err)
+			assert.Equal(t, 6, sum)
+			assert.Equal(t, int32(3), atomic.LoadInt32(&peeked))
+		}
+	})
+
+	t.Run("test panic", func(t *testing.T) {
+		s := NewParallelFloat64Stream([]float64{1, 2, 3}).Peek(func(val float64) {
+			if val == 3 {
+				panic("boom")
+			}
+		})
+		assert.NoError(t, s.Err())
+		_, err := s.Sum()
+		var p *PanicError
+		if assert.True(t, errors.As(err, &p)) {
+			assert.Equal(t, "Peek", p.Op)
+			assert.Equal(t, 2, p.Index)
+		}
+	})
+
+	assert.Error(t, testErrIntStream.ForEach(func(val int) {}))
+	assert.Error(t, testErrIntStream.ForEachOrdered(func(val int) {}))
+	assert.Error(t, testErrIntStream.Peek(func(val int) {}).Err())
+}
+
+func TestTypedStreamForEach(t *testing.T) {
+	visited := make([]string, 0)
+	err := NewParallelTypedStream([]string{"a", "b", "c"}).Peek(func(val string) {}).ForEachOrdered(func(val string) {
+		visited = append(visited, val)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, visited)
+}
@@ -1,162 +1,152 @@
 package gostream
 
-import "sync"
-
-type recursiveAction struct {
-	compute func()
-	wg      sync.WaitGroup
-}
-
-type recursiveTask struct {
-	*recursiveAction
-	compute func() interface{}
-	result  interface{}
-}
+import "sort"
 
 type reduceRecursiveTask struct {
-	*recursiveTask
+	op          string
 	accumulator func(a, b interface{}) interface{}
 	elements    []*element
 	start, end  int
 }
 
+// foldRecursiveTask folds the elements of each leaf into identity with accumulator, and merges the partial results
+// with combiner.
+type foldRecursiveTask struct {
+	op          string
+	identity    interface{}
+	accumulator func(acc, val interface{}) interface{}
+	combiner    func(a, b interface{}) interface{}
+	elements    []*element
+	start, end  int
+}
+
 type sortRecursiveAction struct {
-	*recursiveAction
 	less       func(a, b *element) bool
 	elements   []*element
 	aux        []*element
 	start, end int
 }
 
-type intReduceRecursiveTask struct {
-	*recursiveTask
-	accumulator func(a, b int) int
-	elements    []int
-	start, end  int
-}
-
-type f64ReduceRecursiveTask struct {
-	*recursiveTask
-	accumulator func(a, b float64) float64
-	elements    []float64
+// numberReduceRecursiveTask reduces the numbers with accumulator, each leaf starts from identity if it's not nil, or
+// from its first number otherwise.
+type numberReduceRecursiveTask[N Number] struct {
+	op          string
+	identity    *N
+	accumulator func(a, b N) N
+	elements    []N
 	start, end  int
 }
 
-func newRecursiveTask() *recursiveTask {
-	r := &recursiveTask{
-		recursiveAction: &recursiveAction{},
-	}
-	r.recursiveAction.compute = r.actualCompute
-	return r
-}
-
-func newReduceRecursiveTask(accumulator func(a, b interface{}) interface{}, elements []*element, start, end int) *reduceRecursiveTask {
+func newReduceRecursiveTask(op string, accumulator func(a, b interface{}) interface{}, elements []*element, start, end int) *ForkJoinTask {
 	r := &reduceRecursiveTask{
-		recursiveTask: newRecursiveTask(),
-		accumulator:   accumulator,
-		elements:      elements,
-		start:         start,
-		end:           end,
+		op:          op,
+		accumulator: accumulator,
+		elements:    elements,
+		start:       start,
+		end:         end,
 	}
-	r.recursiveTask.compute = r.compute
-	return r
-}
-
-func newSortRecursiveAction(less func(a, b *element) bool, elements, aux []*element, start, end int) *sortRecursiveAction {
-	s := &sortRecursiveAction{
-		recursiveAction: &recursiveAction{},
-		less:            less,
-		elements:        elements,
-		aux:             aux,
-		start:           start,
-		end:             end,
+	return NewForkJoinTask(r.compute)
+}
+
+func newFoldRecursiveTask(op string, identity interface{}, accumulator func(acc, val interface{}) interface{},
+	combiner func(a, b interface{}) interface{}, elements []*element, start, end int) *ForkJoinTask {
+	f := &foldRecursiveTask{
+		op:          op,
+		identity:    identity,
+		accumulator: accumulator,
+		combiner:    combiner,
+		elements:    elements,
+		start:       start,
+		end:         end,
 	}
-	s.recursiveAction.compute = s.compute
-	return s
+	return NewForkJoinTask(f.compute)
 }
 
-func newIntReduceRecursiveTask(accumulator func(a, b int) int, elements []int, start, end int) *intReduceRecursiveTask {
-	i := &intReduceRecursiveTask{
-		recursiveTask: newRecursiveTask(),
-		accumulator:   accumulator,
-		elements:      elements,
-		start:         start,
-		end:           end,
+func newSortRecursiveAction(less func(a, b *element) bool, elements, aux []*element, start, end int) *ForkJoinTask {
+	s := &sortRecursiveAction{
+		less:     less,
+		elements: elements,
+		aux:      aux,
+		start:    start,
+		end:      end,
 	}
-	i.recursiveTask.compute = i.compute
-	return i
+	return NewForkJoinTask(s.compute)
 }
 
-func newFloat64ReduceRecursiveTask(accumulator func(a, b float64) float64, elements []float64, start, end int) *f64ReduceRecursiveTask {
-	newRecursiveTask()
-	f := &f64ReduceRecursiveTask{
-		recursiveTask: newRecursiveTask(),
-		accumulator:   accumulator,
-		elements:      elements,
-		start:         start,
-		end:           end,
+func newNumberReduceRecursiveTask[N Number](op string, identity *N, accumulator func(a, b N) N, elements []N, start, end int) *ForkJoinTask {
+	n := &numberReduceRecursiveTask[N]{
+		op:          op,
+		identity:    identity,
+		accumulator: accumulator,
+		elements:    elements,
+		start:       start,
+		end:         end,
 	}
-	f.recursiveTask.compute = f.compute
-	return f
+	return NewForkJoinTask(n.compute)
 }
 
-func (r *recursiveAction) fork() {
-	r.wg.Add(1)
-	go func() {
-		defer r.wg.Done()
-		r.compute()
+func (r *reduceRecursiveTask) compute(w *ForkJoinWorker) interface{} {
+	index := r.start + 1
+	defer func() {
+		if v := recover(); v != nil {
+			panic(newPanicError(r.op, index, v))
+		}
 	}()
-}
-
-func (r *recursiveAction) join() {
-	r.wg.Wait()
-}
-
-func (r *recursiveTask) actualCompute() {
-	r.result = r.compute()
-}
-
-func (r *recursiveTask) join() interface{} {
-	r.recursiveAction.join()
-	return r.result
-}
-
-func (r *reduceRecursiveTask) compute() interface{} {
-	length := r.end - r.start + 1
-	if length == 1 {
-		return r.elements[r.start].data
-	}
-	if length == 2 {
-		return r.accumulator(r.elements[r.start].data, r.elements[r.end].data)
+	if r.end-r.start+1 <= w.Pool().Threshold() {
+		result := r.elements[r.start].data
+		for ; index <= r.end; index++ {
+			result = r.accumulator(result, r.elements[index].data)
+		}
+		return result
 	}
 	mid := (r.start + r.end) >> 1
-	left := newReduceRecursiveTask(r.accumulator, r.elements, r.start, mid)
-	left.fork()
-	right := newReduceRecursiveTask(r.accumulator, r.elements, mid+1, r.end).compute()
-	return r.accumulator(left.join(), right)
-}
-
-func (s *sortRecursiveAction) compute() {
-	length := s.end - s.start + 1
-	if length <= 1 {
-		return
-	}
-	if length == 2 {
-		if s.less(s.elements[s.end], s.elements[s.start]) {
-			tmp := s.elements[s.start]
-			s.elements[s.start] = s.elements[s.end]
-			s.elements[s.end] = tmp
+	left := newReduceRecursiveTask(r.op, r.accumulator, r.elements, r.start, mid)
+	w.Fork(left)
+	right := (&reduceRecursiveTask{op: r.op, accumulator: r.accumulator, elements: r.elements, start: mid + 1, end: r.end}).compute(w)
+	leftResult := w.Join(left)
+	index = mid + 1
+	return r.accumulator(leftResult, right)
+}
+
+func (f *foldRecursiveTask) compute(w *ForkJoinWorker) interface{} {
+	index := f.start
+	defer func() {
+		if v := recover(); v != nil {
+			panic(newPanicError(f.op, index, v))
 		}
-		return
+	}()
+	if f.end-f.start+1 <= w.Pool().Threshold() {
+		result := f.identity
+		for ; index <= f.end; index++ {
+			result = f.accumulator(result, f.elements[index].data)
+		}
+		return result
+	}
+	mid := (f.start + f.end) >> 1
+	left := newFoldRecursiveTask(f.op, f.identity, f.accumulator, f.combiner, f.elements, f.start, mid)
+	w.Fork(left)
+	right := (&foldRecursiveTask{op: f.op, identity: f.identity, accumulator: f.accumulator, combiner: f.combiner,
+		elements: f.elements, start: mid + 1, end: f.end}).compute(w)
+	leftResult := w.Join(left)
+	index = -1
+	return f.combiner(leftResult, right)
+}
+
+func (s *sortRecursiveAction) compute(w *ForkJoinWorker) interface{} {
+	if s.end-s.start+1 <= w.Pool().Threshold() {
+		segment := s.elements[s.start : s.end+1]
+		sort.SliceStable(segment, func(i, j int) bool {
+			return s.less(segment[i], segment[j])
+		})
+		return nil
 	}
 	mid := (s.start + s.end) >> 1
 	left := newSortRecursiveAction(s.less, s.elements, s.aux, s.start, mid)
-	left.fork()
-	right := newSortRecursiveAction(s.less, s.elements, s.aux, mid+1, s.end)
-	right.compute()
-	left.join()
+	w.Fork(left)
+	(&sortRecursiveAction{less: s.less, elements: s.elements, aux: s.aux, start: mid + 1, end: s.end}).compute(w)
+	w.Join(left)
 
-	// merge
+	// merge, the element of the left half goes first unless the element of the right half is less, to keep stable
 	for i := s.start; i <= s.end; i++ {
 		s.aux[i] = s.elements[i]
 	}
@@ -168,42 +158,40 @@ func (s *sortRecursiveAction) compute() {
 		} else if j > s.end {
 			s.elements[k] = s.aux[i]
 			i++
-		} else if s.less(s.aux[i], s.aux[j]) {
-			s.elements[k] = s.aux[i]
-			i++
-		} else {
+		} else if s.less(s.aux[j], s.aux[i]) {
 			s.elements[k] = s.aux[j]
 			j++
+		} else {
+			s.elements[k] = s.aux[i]
+			i++
 		}
 	}
+	return nil
 }
 
-func (i *intReduceRecursiveTask) compute() interface{} {
-	length := i.end - i.start + 1
-	if length == 1 {
-		return i.elements[i.start]
-	}
-	if length == 2 {
-		return i.accumulator(i.elements[i.start], i.elements[i.end])
-	}
-	mid := (i.start + i.end) >> 1
-	left := newIntReduceRecursiveTask(i.accumulator, i.elements, i.start, mid)
-	left.fork()
-	right := newIntReduceRecursiveTask(i.accumulator, i.elements, mid+1, i.end).compute()
-	return i.accumulator(left.join().(int), right.(int))
-}
-
-func (f *f64ReduceRecursiveTask) compute() interface{} {
-	length := f.end - f.start + 1
-	if length == 1 {
-		return f.elements[f.start]
-	}
-	if length == 2 {
-		return f.accumulator(f.elements[f.start], f.elements[f.end])
+func (n *numberReduceRecursiveTask[N]) compute(w *ForkJoinWorker) interface{} {
+	index := n.start + 1
+	defer func() {
+		if v := recover(); v != nil {
+			panic(newPanicError(n.op, index, v))
+		}
+	}()
+	if n.end-n.start+1 <= w.Pool().Threshold() {
+		result := n.elements[n.start]
+		if n.identity != nil {
+			result = *n.identity
+			index = n.start
+		}
+		for ; index <= n.end; index++ {
+			result = n.accumulator(result, n.elements[index])
+		}
+		return result
 	}
-	mid := (f.start + f.end) >> 1
-	left := newFloat64ReduceRecursiveTask(f.accumulator, f.elements, f.start, mid)
-	left.fork()
-	right := newFloat64ReduceRecursiveTask(f.accumulator, f.elements, mid+1, f.end).compute()
-	return f.accumulator(left.join().(float64), right.(float64))
+	mid := (n.start + n.end) >> 1
+	left := newNumberReduceRecursiveTask(n.op, n.identity, n.accumulator, n.elements, n.start, mid)
+	w.Fork(left)
+	right := (&numberReduceRecursiveTask[N]{op: n.op, identity: n.identity, accumulator: n.accumulator, elements: n.elements, start: mid + 1, end: n.end}).compute(w)
+	leftResult := w.Join(left)
+	index = mid + 1
+	return n.accumulator(leftResult.(N), right.(N))
 }
@@ -0,0 +1,291 @@
+package gostream
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultForkJoinThreshold is the sequential threshold of the pools whose threshold is not specified.
+const DefaultForkJoinThreshold = 1 << 10
+
+var (
+	defaultForkJoinPool atomic.Value
+	// defaultForkJoinPoolMu serializes the replacements of the default pool.
+	defaultForkJoinPoolMu sync.Mutex
+)
+
+// ForkJoinPool runs ForkJoinTasks on a fixed set of worker goroutines. Every worker owns a deque of tasks: the tasks
+// forked by a worker are pushed to and popped from the top of its deque, and an idle worker steals the oldest task
+// from the bottom of the deque of another worker, which is usually the largest piece of work left.
+// The goroutine calling Invoke works as a worker until the task is done, so the pool never deadlocks even if all the
+// workers are busy.
+type ForkJoinPool struct {
+	parallelism int
+	threshold   int
+	// workers is a []*ForkJoinWorker holding the workers of the pool and the goroutines calling Invoke, it's
+	// replaced under mu and read without lock by the thieves.
+	workers atomic.Value
+	mu      sync.Mutex
+	// signal wakes up the idle workers when tasks are forked.
+	signal chan struct{}
+	done   chan struct{}
+	start  sync.Once
+	stop   sync.Once
+}
+
+// ForkJoinWorker is the handle of a goroutine running the tasks of a ForkJoinPool, it's passed to the running task to
+// fork and join its subtasks.
+type ForkJoinWorker struct {
+	pool  *ForkJoinPool
+	mu    sync.Mutex
+	deque []*ForkJoinTask
+}
+
+// ForkJoinTask is a task run by a ForkJoinPool, which may split its work into subtasks by forking and joining them.
+type ForkJoinTask struct {
+	compute func(w *ForkJoinWorker) interface{}
+	result  interface{}
+	// panicked is the value recovered from compute, which is panicked again when the task is joined.
+	panicked interface{}
+	done     chan struct{}
+}
+
+// forkJoinPanic wraps the value recovered from a task, so that a nil value can be panicked again.
+type forkJoinPanic struct {
+	value interface{}
+}
+
+func init() {
+	defaultForkJoinPool.Store(NewForkJoinPool(0, 0))
+}
+
+// NewForkJoinPool returns a pool running the tasks with the specified number of worker goroutines,
+// runtime.GOMAXPROCS(0) workers are used if parallelism is not positive.
+// threshold is the size below which the tasks should compute inline instead of forking subtasks, it's only a hint
+// for the tasks obtained by Threshold, and DefaultForkJoinThreshold is used if it's not positive.
+// The workers are started on the first use of the pool.
+func NewForkJoinPool(parallelism, threshold int) *ForkJoinPool {
+	if parallelism <= 0 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+	if threshold <= 0 {
+		threshold = DefaultForkJoinThreshold
+	}
+	p := &ForkJoinPool{
+		parallelism: parallelism,
+		threshold:   threshold,
+		signal:      make(chan struct{}, parallelism),
+		done:        make(chan struct{}),
+	}
+	p.workers.Store([]*ForkJoinWorker(nil))
+	return p
+}
+
+// DefaultForkJoinPool returns the pool shared by all the parallel streams.
+func DefaultForkJoinPool() *ForkJoinPool {
+	return defaultForkJoinPool.Load().(*ForkJoinPool)
+}
+
+// SetDefaultForkJoinPool replaces the pool shared by all the parallel streams, the previous pool is returned and it's
+// not closed.
+func SetDefaultForkJoinPool(p *ForkJoinPool) (previous *ForkJoinPool) {
+	defaultForkJoinPoolMu.Lock()
+	defer defaultForkJoinPoolMu.Unlock()
+	previous = DefaultForkJoinPool()
+	defaultForkJoinPool.Store(p)
+	return previous
+}
+
+// NewForkJoinTask returns a task which computes its result with compute, w is the worker running the task.
+func NewForkJoinTask(compute func(w *ForkJoinWorker) interface{}) *ForkJoinTask {
+	return &ForkJoinTask{compute: compute, done: make(chan struct{})}
+}
+
+// Parallelism returns the number of worker goroutines of the pool.
+func (p *ForkJoinPool) Parallelism() int {
+	return p.parallelism
+}
+
+// Threshold returns the size below which the tasks should compute inline instead of forking subtasks.
+func (p *ForkJoinPool) Threshold() int {
+	return p.threshold
+}
+
+// Invoke runs task and its subtasks in the pool, and returns the result of task after it's done.
+// The calling goroutine runs task itself, and works as a worker of the pool until task is done. The value panicked
+// by task is panicked again by Invoke.
+func (p *ForkJoinPool) Invoke(task *ForkJoinTask) interface{} {
+	p.start.Do(func() {
+		for i := 0; i < p.parallelism; i++ {
+			go p.register().work()
+		}
+	})
+	w := p.register()
+	defer p.unregister(w)
+	task.run(w)
+	// run the subtasks forked but not joined by task, nobody could steal them once w is unregistered
+	for t := w.pop(); t != nil; t = w.pop() {
+		t.run(w)
+	}
+	return task.get()
+}
+
+// Close stops the worker goroutines of the pool. The tasks invoked after closing are run by the calling goroutines.
+func (p *ForkJoinPool) Close() {
+	p.stop.Do(func() {
+		close(p.done)
+	})
+}
+
+func (p *ForkJoinPool) register() *ForkJoinWorker {
+	w := &ForkJoinWorker{pool: p}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	workers := p.workers.Load().([]*ForkJoinWorker)
+	p.workers.Store(append(workers[:len(workers):len(workers)], w))
+	return w
+}
+
+func (p *ForkJoinPool) unregister(w *ForkJoinWorker) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	workers := p.workers.Load().([]*ForkJoinWorker)
+	remain := make([]*ForkJoinWorker, 0, len(workers))
+	for _, worker := range workers {
+		if worker != w {
+			remain = append(remain, worker)
+		}
+	}
+	p.workers.Store(remain)
+}
+
+// notify wakes up an idle worker. The signal is dropped if enough signals are pending, which will wake up the
+// workers anyway.
+func (p *ForkJoinPool) notify() {
+	select {
+	case p.signal <- struct{}{}:
+	default:
+	}
+}
+
+// steal takes the oldest task from the deque of a worker other than thief, the victims are scanned from a random
+// worker to spread the contention. nil is returned if all the deques are empty.
+func (p *ForkJoinPool) steal(thief *ForkJoinWorker) *ForkJoinTask {
+	workers := p.workers.Load().([]*ForkJoinWorker)
+	if len(workers) == 0 {
+		return nil
+	}
+	offset := rand.Intn(len(workers))
+	for i := range workers {
+		victim := workers[(offset+i)%len(workers)]
+		if victim == thief {
+			continue
+		}
+		if t := victim.take(); t != nil {
+			return t
+		}
+	}
+	return nil
+}
+
+// Pool returns the pool the worker belongs to.
+func (w *ForkJoinWorker) Pool() *ForkJoinPool {
+	return w.pool
+}
+
+// Fork pushes task to the deque of the worker, task will be run by the worker when it's joined, unless it's stolen
+// by an idle worker before.
+func (w *ForkJoinWorker) Fork(task *ForkJoinTask) {
+	w.mu.Lock()
+	w.deque = append(w.deque, task)
+	w.mu.Unlock()
+	w.pool.notify()
+}
+
+// Join returns the result of task after it's done, the worker runs the tasks of its own deque and steals the tasks of
+// the other workers while task is not done. The value panicked by task is panicked again by Join.
+func (w *ForkJoinWorker) Join(task *ForkJoinTask) interface{} {
+	for !task.isDone() {
+		if t := w.pop(); t != nil {
+			t.run(w)
+		} else if t := w.pool.steal(w); t != nil {
+			t.run(w)
+		} else {
+			// task has been taken by another worker, and there is nothing to help with
+			<-task.done
+		}
+	}
+	return task.get()
+}
+
+func (w *ForkJoinWorker) work() {
+	for {
+		if t := w.pop(); t != nil {
+			t.run(w)
+		} else if t := w.pool.steal(w); t != nil {
+			t.run(w)
+		} else {
+			select {
+			case <-w.pool.signal:
+			case <-w.pool.done:
+				w.pool.unregister(w)
+				return
+			}
+		}
+	}
+}
+
+// pop takes the newest task from the top of the deque of the worker.
+func (w *ForkJoinWorker) pop() *ForkJoinTask {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	n := len(w.deque)
+	if n == 0 {
+		return nil
+	}
+	t := w.deque[n-1]
+	w.deque[n-1] = nil
+	w.deque = w.deque[:n-1]
+	return t
+}
+
+// take takes the oldest task from the bottom of the deque of the worker.
+func (w *ForkJoinWorker) take() *ForkJoinTask {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.deque) == 0 {
+		return nil
+	}
+	t := w.deque[0]
+	w.deque[0] = nil
+	w.deque = w.deque[1:]
+	return t
+}
+
+func (t *ForkJoinTask) run(w *ForkJoinWorker) {
+	defer close(t.done)
+	defer func() {
+		if r := recover(); r != nil {
+			t.panicked = forkJoinPanic{r}
+		}
+	}()
+	t.result = t.compute(w)
+}
+
+func (t *ForkJoinTask) isDone() bool {
+	select {
+	case <-t.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *ForkJoinTask) get() interface{} {
+	if p, ok := t.panicked.(forkJoinPanic); ok {
+		panic(p.value)
+	}
+	return t.result
+}
@@ -0,0 +1,135 @@
+package gostream
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"testing"
+)
+
+func sumTask(elements []int) *ForkJoinTask {
+	return NewForkJoinTask(func(w *ForkJoinWorker) interface{} {
+		if len(elements) <= w.Pool().Threshold() {
+			sum := 0
+			for _, e := range elements {
+				sum += e
+			}
+			return sum
+		}
+		mid := len(elements) / 2
+		left, right := sumTask(elements[:mid]), sumTask(elements[mid:])
+		w.Fork(left)
+		w.Fork(right)
+		return w.Join(right).(int) + w.Join(left).(int)
+	})
+}
+
+func TestNewForkJoinPool(t *testing.T) {
+	p := NewForkJoinPool(3, 10)
+	defer p.Close()
+	assert.Equal(t, 3, p.Parallelism())
+	assert.Equal(t, 10, p.Threshold())
+
+	p = NewForkJoinPool(0, 0)
+	defer p.Close()
+	assert.True(t, p.Parallelism() > 0)
+	assert.Equal(t, DefaultForkJoinThreshold, p.Threshold())
+}
+
+func TestForkJoinPool_Invoke(t *testing.T) {
+	elements := make([]int, 10000)
+	for i := range elements {
+		elements[i] = i
+	}
+	for _, threshold := range []int{1, 7, 100000} {
+		p := NewForkJoinPool(4, threshold)
+		assert.Equal(t, 49995000, p.Invoke(sumTask(elements)))
+		p.Close()
+	}
+
+	t.Run("test concurrent invokes", func(t *testing.T) {
+		p := NewForkJoinPool(2, 10)
+		defer p.Close()
+		results := make(chan interface{})
+		for i := 0; i < 8; i++ {
+			go func() {
+				results <- p.Invoke(sumTask(elements))
+			}()
+		}
+		for i := 0; i < 8; i++ {
+			assert.Equal(t, 49995000, <-results)
+		}
+	})
+
+	t.Run("test closed", func(t *testing.T) {
+		p := NewForkJoinPool(2, 10)
+		p.Close()
+		assert.Equal(t, 49995000, p.Invoke(sumTask(elements)))
+	})
+
+	t.Run("test not joined", func(t *testing.T) {
+		p := NewForkJoinPool(2, 10)
+		defer p.Close()
+		forked := NewForkJoinTask(func(*ForkJoinWorker) interface{} {
+			return 1
+		})
+		p.Invoke(NewForkJoinTask(func(w *ForkJoinWorker) interface{} {
+			w.Fork(forked)
+			return nil
+		}))
+		<-forked.done
+	})
+
+	t.Run("test panic", func(t *testing.T) {
+		p := NewForkJoinPool(2, 10)
+		defer p.Close()
+		assert.PanicsWithValue(t, "boom", func() {
+			p.Invoke(NewForkJoinTask(func(w *ForkJoinWorker) interface{} {
+				sub := NewForkJoinTask(func(*ForkJoinWorker) interface{} {
+					panic("boom")
+				})
+				w.Fork(sub)
+				return w.Join(sub)
+			}))
+		})
+	})
+}
+
+func TestSetDefaultForkJoinPool(t *testing.T) {
+	p := NewForkJoinPool(4, 2)
+	previous := SetDefaultForkJoinPool(p)
+	defer func() {
+		SetDefaultForkJoinPool(previous)
+		p.Close()
+	}()
+	assert.Same(t, p, DefaultForkJoinPool())
+
+	type pair struct{ key, index int }
+	pairs := make([]pair, 1000)
+	ints := make([]int, len(pairs))
+	for i := range pairs {
+		pairs[i] = pair{key: (i * 7919) % 13, index: i}
+		ints[i] = i
+	}
+	var res []pair
+	assert.NoError(t, NewParallelStream(pairs).Sorted(func(a, b interface{}) bool {
+		return a.(pair).key < b.(pair).key
+	}).Collect(&res))
+	assert.True(t, sort.SliceIsSorted(res, func(i, j int) bool {
+		if res[i].key != res[j].key {
+			return res[i].key < res[j].key
+		}
+		return res[i].index < res[j].index
+	}))
+
+	sum, err := NewParallelStream(ints).Reduce(func(a, b interface{}) (c interface{}) {
+		return a.(int) + b.(int)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 499500, sum)
+
+	intSum, err := NewParallelIntStream(ints).Reduce(func(a, b int) (c int) {
+		return a + b
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 499500, *intSum)
+}
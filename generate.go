@@ -0,0 +1,428 @@
+package gostream
+
+import (
+	"context"
+	"fmt"
+)
+
+// generateIterator produces the elements returned by supplier endlessly, until the context is done.
+type generateIterator struct {
+	ctx      context.Context
+	supplier func() interface{}
+	index    int
+	e        error
+}
+
+// iterateIterator produces seed, and then the results of applying next to the previous element, until hasNext returns
+// false or the context is done.
+type iterateIterator struct {
+	ctx     context.Context
+	current interface{}
+	hasNext func(val interface{}) bool
+	step    func(prev interface{}) interface{}
+	index   int
+	done    bool
+	e       error
+}
+
+// rangeIterator produces the numbers from start to end by an incremental step of 1.
+type rangeIterator[N Number] struct {
+	ctx     context.Context
+	current N
+	end     N
+	closed  bool
+	done    bool
+	e       error
+}
+
+// lazyNumberStream is the NumberStream whose elements are pulled through the pipeline of a Stream, so that an
+// infinite source can be bounded by Limit. The operations needing all the elements evaluate the pipeline into an
+// eager NumberStream first.
+type lazyNumberStream[N Number] struct {
+	typedStream[N]
+}
+
+// Generate returns an infinite sequential stream whose elements are generated by supplier, which is called once an
+// element is pulled, so the stream should be bounded by a short-circuiting operation such as Limit.
+// The parallel operations applied to each element on its own, such as Map or Filter, pull the stream in batches, but
+// the ones needing all the elements, such as Sorted or GroupBy, should be called after the stream was bounded.
+func Generate(supplier func() interface{}) Stream {
+	return newSequentialStream(config{}, func(ctx context.Context) iterator {
+		return &generateIterator{ctx: ctx, supplier: supplier}
+	})
+}
+
+// Iterate returns a sequential ordered stream produced by iterative application of next to seed, the stream
+// terminates once hasNext returns false for the element, or it's infinite if hasNext is nil.
+// The elements are computed once they are pulled, so an infinite stream should be bounded by a short-circuiting
+// operation such as Limit, and the parallel operations needing all the elements, such as Sorted or GroupBy, should be
+// called after the stream was bounded.
+func Iterate(seed interface{}, hasNext func(val interface{}) bool, next func(prev interface{}) interface{}) Stream {
+	return newSequentialStream(config{}, func(ctx context.Context) iterator {
+		return &iterateIterator{ctx: ctx, current: seed, hasNext: hasNext, step: next}
+	})
+}
+
+// NumberGenerate is the Generate producing a NumberStream.
+func NumberGenerate[N Number](supplier func() N) NumberStream[N] {
+	return newLazyNumberStream[N](Generate(func() interface{} {
+		return supplier()
+	}))
+}
+
+// NumberIterate is the Iterate producing a NumberStream, the stream is infinite if hasNext is nil.
+func NumberIterate[N Number](seed N, hasNext func(val N) bool, next func(prev N) N) NumberStream[N] {
+	var untypedHasNext func(val interface{}) bool
+	if hasNext != nil {
+		untypedHasNext = func(val interface{}) bool {
+			return hasNext(val.(N))
+		}
+	}
+	return newLazyNumberStream[N](Iterate(seed, untypedHasNext, func(prev interface{}) interface{} {
+		return next(prev.(N))
+	}))
+}
+
+// NumberRange returns a sequential ordered stream from start (inclusive) to end (exclusive) by an incremental step
+// of 1. The numbers are produced once they are pulled, so a large range doesn't occupy any memory.
+func NumberRange[N Number](start, end N) NumberStream[N] {
+	return newRangeStream(start, end, false)
+}
+
+// NumberRangeClosed returns a sequential ordered stream from start (inclusive) to end (inclusive) by an incremental
+// step of 1.
+func NumberRangeClosed[N Number](start, end N) NumberStream[N] {
+	return newRangeStream(start, end, true)
+}
+
+// IntRange returns a sequential ordered IntStream from start (inclusive) to end (exclusive) by an incremental step
+// of 1.
+func IntRange(start, end int) IntStream {
+	return NumberRange(start, end)
+}
+
+// IntRangeClosed returns a sequential ordered IntStream from start (inclusive) to end (inclusive) by an incremental
+// step of 1.
+func IntRangeClosed(start, end int) IntStream {
+	return NumberRangeClosed(start, end)
+}
+
+// IntIterate returns an infinite sequential ordered IntStream produced by iterative application of next to seed.
+func IntIterate(seed int, next func(prev int) int) IntStream {
+	return NumberIterate(seed, nil, next)
+}
+
+// IntGenerate returns an infinite sequential IntStream whose elements are generated by supplier.
+func IntGenerate(supplier func() int) IntStream {
+	return NumberGenerate(supplier)
+}
+
+// Float64Iterate returns an infinite sequential ordered Float64Stream produced by iterative application of next to
+// seed.
+func Float64Iterate(seed float64, next func(prev float64) float64) Float64Stream {
+	return NumberIterate(seed, nil, next)
+}
+
+// Float64Generate returns an infinite sequential Float64Stream whose elements are generated by supplier.
+func Float64Generate(supplier func() float64) Float64Stream {
+	return NumberGenerate(supplier)
+}
+
+func newRangeStream[N Number](start, end N, closed bool) NumberStream[N] {
+	return newLazyNumberStream[N](newSequentialStream(config{}, func(ctx context.Context) iterator {
+		return &rangeIterator[N]{ctx: ctx, current: start, end: end, closed: closed}
+	}))
+}
+
+func newLazyNumberStream[N Number](s Stream) *lazyNumberStream[N] {
+	return &lazyNumberStream[N]{typedStream: typedStream[N]{s: s}}
+}
+
+func (g *generateIterator) next() (*element, bool) {
+	if g.e != nil {
+		return nil, false
+	}
+	if g.e = ctxErr(g.ctx); g.e != nil {
+		return nil, false
+	}
+	defer catch("Generate", g.index)
+	g.index++
+	return newElement(g.supplier()), true
+}
+
+func (g *generateIterator) err() error {
+	return g.e
+}
+
+func (it *iterateIterator) next() (*element, bool) {
+	if it.done || it.e != nil {
+		return nil, false
+	}
+	if it.e = ctxErr(it.ctx); it.e != nil {
+		return nil, false
+	}
+	defer catch("Iterate", it.index)
+	if it.index > 0 {
+		it.current = it.step(it.current)
+	}
+	if it.hasNext != nil && !it.hasNext(it.current) {
+		it.done = true
+		return nil, false
+	}
+	it.index++
+	return newElement(it.current), true
+}
+
+func (it *iterateIterator) err() error {
+	return it.e
+}
+
+func (r *rangeIterator[N]) next() (*element, bool) {
+	if r.done || r.e != nil {
+		return nil, false
+	}
+	if r.e = ctxErr(r.ctx); r.e != nil {
+		return nil, false
+	}
+	if r.current > r.end || !r.closed && r.current == r.end {
+		r.done = true
+		return nil, false
+	}
+	e := newElement(r.current)
+	// stop at end rather than incrementing past it, which may overflow
+	if r.current == r.end {
+		r.done = true
+	} else {
+		r.current++
+	}
+	return e, true
+}
+
+func (r *rangeIterator[N]) err() error {
+	return r.e
+}
+
+func (l *lazyNumberStream[N]) with(s Stream) NumberStream[N] {
+	return newLazyNumberStream[N](s)
+}
+
+// evaluate pulls all the elements into an eager NumberStream with the same configuration.
+func (l *lazyNumberStream[N]) evaluate() NumberStream[N] {
+	elements, err := l.Collect()
+	if err != nil {
+		return &errNumberStream[N]{err: err, parallel: l.IsParallel()}
+	}
+	switch s := l.s.(type) {
+	case *sequentialStream:
+		return &sequentialNumberStream[N]{elements: elements, config: s.config}
+	case *parallelStream:
+		return &parallelNumberStream[N]{elements: elements, config: s.config}
+	}
+	return NewSequentialNumberStream(elements)
+}
+
+func (l *lazyNumberStream[N]) Average() (*float64, error) {
+	return l.evaluate().Average()
+}
+
+func (l *lazyNumberStream[N]) Chunk(n int) Stream {
+	return typedSlices[N](l.s.Chunk(n))
+}
+
+func (l *lazyNumberStream[N]) Distinct() NumberStream[N] {
+	return l.with(l.s.Distinct(func(obj interface{}) interface{} {
+		return obj
+	}, func(a, b interface{}) bool {
+		return a.(N) == b.(N)
+	}))
+}
+
+func (l *lazyNumberStream[N]) DropWhile(predicate func(val N) (match bool)) NumberStream[N] {
+	return l.with(l.s.DropWhile(untypedPredicate(predicate)))
+}
+
+func (l *lazyNumberStream[N]) Filter(predicate func(val N) (match bool)) NumberStream[N] {
+	return l.with(l.s.Filter(untypedPredicate(predicate)))
+}
+
+func (l *lazyNumberStream[N]) FilterE(predicate func(val N) (match bool, err error)) NumberStream[N] {
+	return l.with(l.s.FilterE(func(val interface{}) (match bool, err error) {
+		return predicate(val.(N))
+	}))
+}
+
+func (l *lazyNumberStream[N]) FlatMap(mapper func(val N) NumberStream[N]) NumberStream[N] {
+	return l.with(l.s.FlatMap(func(val interface{}) Stream {
+		return untypedNumberStream(mapper(val.(N)))
+	}))
+}
+
+func (l *lazyNumberStream[N]) FlatMapE(mapper func(val N) (NumberStream[N], error)) NumberStream[N] {
+	return l.with(l.s.FlatMapE(func(val interface{}) (Stream, error) {
+		stream, err := mapper(val.(N))
+		if err != nil {
+			return nil, err
+		}
+		return untypedNumberStream(stream), nil
+	}))
+}
+
+func (l *lazyNumberStream[N]) Limit(maxSize int) NumberStream[N] {
+	if maxSize < 0 {
+		return &errNumberStream[N]{err: fmt.Errorf("limit error, maxSize less than 0: %d", maxSize), parallel: l.IsParallel()}
+	}
+	return l.with(l.s.Limit(maxSize))
+}
+
+func (l *lazyNumberStream[N]) Map(mapper func(src N) (dest N)) NumberStream[N] {
+	return l.with(l.s.Map(func(src interface{}) (dest interface{}) {
+		return mapper(src.(N))
+	}))
+}
+
+func (l *lazyNumberStream[N]) MapE(mapper func(src N) (dest N, err error)) NumberStream[N] {
+	return l.with(l.s.MapE(func(src interface{}) (dest interface{}, err error) {
+		return mapper(src.(N))
+	}))
+}
+
+func (l *lazyNumberStream[N]) MapToFloat64(mapper func(src N) (dest float64)) Float64Stream {
+	return newLazyNumberStream[float64](l.s.Map(func(src interface{}) (dest interface{}) {
+		return mapper(src.(N))
+	}))
+}
+
+func (l *lazyNumberStream[N]) MapToInt(mapper func(src N) (dest int)) IntStream {
+	return newLazyNumberStream[int](l.s.Map(func(src interface{}) (dest interface{}) {
+		return mapper(src.(N))
+	}))
+}
+
+func (l *lazyNumberStream[N]) MapToInt64(mapper func(src N) (dest int64)) Int64Stream {
+	return newLazyNumberStream[int64](l.s.Map(func(src interface{}) (dest interface{}) {
+		return mapper(src.(N))
+	}))
+}
+
+func (l *lazyNumberStream[N]) MapToObj(mapper func(src N) (dest interface{})) Stream {
+	return l.s.Map(func(src interface{}) (dest interface{}) {
+		return mapper(src.(N))
+	})
+}
+
+func (l *lazyNumberStream[N]) MapToString(mapper func(src N) (dest string)) StringStream {
+	return newStringStream(l.s.Map(func(src interface{}) (dest interface{}) {
+		return mapper(src.(N))
+	}))
+}
+
+func (l *lazyNumberStream[N]) Max() (*N, error) {
+	return l.evaluate().Max()
+}
+
+func (l *lazyNumberStream[N]) Median() (*float64, error) {
+	return l.evaluate().Median()
+}
+
+func (l *lazyNumberStream[N]) Min() (*N, error) {
+	return l.evaluate().Min()
+}
+
+func (l *lazyNumberStream[N]) MovingAverage(size int) Float64Stream {
+	if size <= 0 {
+		err := fmt.Errorf("moving average error, size is not positive: %d", size)
+		return &errFloat64Stream{err: err, parallel: l.IsParallel()}
+	}
+	windows := typedSlices[N](l.s.Window(size, 1))
+	return newLazyNumberStream[float64](windows.Map(func(src interface{}) (dest interface{}) {
+		return averageNumbers(src.([]N))
+	}))
+}
+
+func (l *lazyNumberStream[N]) Parallel() NumberStream[N] {
+	return l.with(l.s.Parallel())
+}
+
+func (l *lazyNumberStream[N]) Peek(action func(val N)) NumberStream[N] {
+	return l.with(l.s.Peek(untypedAction(action)))
+}
+
+func (l *lazyNumberStream[N]) Percentile(p float64) (*float64, error) {
+	if err := checkPercentile(p); err != nil {
+		return nil, err
+	}
+	return l.evaluate().Percentile(p)
+}
+
+func (l *lazyNumberStream[N]) ReduceWithIdentity(identity N, op func(a, b N) (c N)) (N, error) {
+	return l.evaluate().ReduceWithIdentity(identity, op)
+}
+
+func (l *lazyNumberStream[N]) Sequential() NumberStream[N] {
+	return l.with(l.s.Sequential())
+}
+
+func (l *lazyNumberStream[N]) Skip(n int) NumberStream[N] {
+	if n < 0 {
+		return &errNumberStream[N]{err: fmt.Errorf("skip error, skipN less than 0: %d", n), parallel: l.IsParallel()}
+	}
+	return l.with(l.s.Skip(n))
+}
+
+func (l *lazyNumberStream[N]) Sorted() NumberStream[N] {
+	return l.with(l.s.Sorted(func(a, b interface{}) bool {
+		return lessNumber(a.(N), b.(N))
+	}))
+}
+
+func (l *lazyNumberStream[N]) SortedDescending() NumberStream[N] {
+	return l.SortedWith(greaterNumber[N])
+}
+
+func (l *lazyNumberStream[N]) SortedWith(less func(a, b N) bool) NumberStream[N] {
+	return l.with(l.s.Sorted(func(a, b interface{}) bool {
+		return less(a.(N), b.(N))
+	}))
+}
+
+func (l *lazyNumberStream[N]) Sum() (N, error) {
+	return l.evaluate().Sum()
+}
+
+func (l *lazyNumberStream[N]) SumChecked() (N, error) {
+	return l.evaluate().SumChecked()
+}
+
+func (l *lazyNumberStream[N]) SummaryStatistics() (*NumberSummaryStatistics[N], error) {
+	return l.evaluate().SummaryStatistics()
+}
+
+func (l *lazyNumberStream[N]) TakeWhile(predicate func(val N) (match bool)) NumberStream[N] {
+	return l.with(l.s.TakeWhile(untypedPredicate(predicate)))
+}
+
+func (l *lazyNumberStream[N]) Window(size, step int) Stream {
+	return typedSlices[N](l.s.Window(size, step))
+}
+
+func (l *lazyNumberStream[N]) WithContext(ctx context.Context) NumberStream[N] {
+	return l.with(l.s.WithContext(ctx))
+}
+
+func (l *lazyNumberStream[N]) WithErrorMode(mode ErrorMode) NumberStream[N] {
+	return l.with(l.s.WithErrorMode(mode))
+}
+
+func (l *lazyNumberStream[N]) WithParallelism(n int) NumberStream[N] {
+	return l.with(l.s.WithParallelism(n))
+}
+
+// untypedNumberStream returns a Stream consisting of the elements of s, a lazy stream stays lazy.
+func untypedNumberStream[N Number](s NumberStream[N]) Stream {
+	if l, ok := s.(*lazyNumberStream[N]); ok {
+		return l.s
+	}
+	return s.MapToObj(func(src N) (dest interface{}) {
+		return src
+	})
+}
@@ -0,0 +1,367 @@
+package gostream
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Run("test limit", func(t *testing.T) {
+		calls := 0
+		var res []int
+		err := Generate(func() interface{} {
+			calls++
+			return calls
+		}).Limit(5).Collect(&res)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, res)
+		assert.Equal(t, 5, calls)
+	})
+
+	t.Run("test map to number", func(t *testing.T) {
+		calls, mapped := 0, 0
+		mapper := func(src interface{}) int {
+			mapped++
+			return src.(int) * 2
+		}
+		s := Generate(func() interface{} {
+			calls++
+			return calls
+		}).MapToInt(mapper)
+		assert.Equal(t, 0, mapped)
+		res, err := s.Limit(3).Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []int{2, 4, 6}, res)
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, 3, mapped)
+
+		sum, err := Generate(func() interface{} {
+			return 1
+		}).MapToFloat64(func(src interface{}) float64 {
+			return float64(src.(int))
+		}).Limit(4).Sum()
+		assert.NoError(t, err)
+		assert.Equal(t, 4.0, sum)
+	})
+
+	t.Run("test parallel after limit", func(t *testing.T) {
+		var res []int
+		err := Generate(func() interface{} {
+			return 2
+		}).Limit(100).Parallel().Map(func(src interface{}) (dest interface{}) {
+			return src.(int) * 3
+		}).Collect(&res)
+		assert.NoError(t, err)
+		assert.Len(t, res, 100)
+		for _, v := range res {
+			assert.Equal(t, 6, v)
+		}
+	})
+
+	t.Run("test parallel before limit", func(t *testing.T) {
+		calls := 0
+		var res []int
+		err := Generate(func() interface{} {
+			calls++
+			return calls
+		}).Parallel().WithParallelism(2).Filter(func(val interface{}) (match bool) {
+			return val.(int)%2 == 0
+		}).Map(func(src interface{}) (dest interface{}) {
+			return src.(int) * 3
+		}).Limit(3).Collect(&res)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{6, 12, 18}, res)
+		// the first batch of Map is filtered from the first 2 batches of the source, the second one is twice as large
+		assert.Equal(t, firstBatchSize(2)*3, calls)
+	})
+
+	t.Run("test find first", func(t *testing.T) {
+		calls := 0
+		res, err := IntGenerate(func() int {
+			calls++
+			return calls * calls
+		}).Filter(func(val int) (match bool) {
+			return val > 50
+		}).FindFirst()
+		assert.NoError(t, err)
+		assert.Equal(t, 64, *res)
+		assert.Equal(t, 8, calls)
+	})
+
+	t.Run("test float64", func(t *testing.T) {
+		sum, err := Float64Generate(func() float64 {
+			return 0.5
+		}).Limit(4).Sum()
+		assert.NoError(t, err)
+		assert.Equal(t, 2.0, sum)
+	})
+
+	t.Run("test panic", func(t *testing.T) {
+		calls := 0
+		var res []int
+		err := Generate(func() interface{} {
+			if calls == 3 {
+				panic("boom")
+			}
+			calls++
+			return calls
+		}).Limit(10).Collect(&res)
+		var p *PanicError
+		if assert.True(t, errors.As(err, &p)) {
+			assert.Equal(t, "Generate", p.Op)
+			assert.Equal(t, 3, p.Index)
+			assert.Equal(t, "boom", p.Value)
+		}
+	})
+
+	t.Run("test context canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		var res []int
+		err := Generate(func() interface{} {
+			calls++
+			if calls == 10 {
+				cancel()
+			}
+			return calls
+		}).WithContext(ctx).Filter(func(element interface{}) (match bool) {
+			return false
+		}).Collect(&res)
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, 10, calls)
+	})
+}
+
+func TestIterate(t *testing.T) {
+	double := func(prev interface{}) interface{} {
+		return prev.(int) * 2
+	}
+	tests := []struct {
+		name   string
+		stream Stream
+		expect []int
+	}{
+		{
+			"test infinite",
+			Iterate(1, nil, double).Limit(5),
+			[]int{1, 2, 4, 8, 16},
+		},
+		{
+			"test has next",
+			Iterate(1, func(val interface{}) bool {
+				return val.(int) < 100
+			}, double),
+			[]int{1, 2, 4, 8, 16, 32, 64},
+		},
+		{
+			"test seed rejected",
+			Iterate(1, func(val interface{}) bool {
+				return false
+			}, double),
+			[]int{},
+		},
+		{
+			"test skip",
+			Iterate(1, nil, double).Skip(2).Limit(3),
+			[]int{4, 8, 16},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := make([]int, 0)
+			err := tt.stream.Collect(&res)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expect, res)
+		})
+	}
+
+	t.Run("test int", func(t *testing.T) {
+		res, err := IntIterate(0, func(prev int) int {
+			return prev + 3
+		}).Limit(4).Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []int{0, 3, 6, 9}, res)
+
+		max, err := NumberIterate(int64(1), func(val int64) bool {
+			return val < 1000
+		}, func(prev int64) int64 {
+			return prev * 10
+		}).Max()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(100), *max)
+	})
+
+	t.Run("test parallel", func(t *testing.T) {
+		inc := func(prev interface{}) interface{} {
+			return prev.(int) + 1
+		}
+		var res []int
+		err := Iterate(0, nil, inc).Parallel().Map(func(src interface{}) (dest interface{}) {
+			return src.(int) * 2
+		}).Limit(3).Collect(&res)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{0, 2, 4}, res)
+
+		match, err := Iterate(0, nil, inc).Parallel().Peek(func(val interface{}) {}).AnyMatch(func(val interface{}) (match bool) {
+			return val.(int) == 100000
+		})
+		assert.NoError(t, err)
+		assert.True(t, match)
+
+		first, err := IntIterate(1, func(prev int) int {
+			return prev * 2
+		}).Parallel().Filter(func(val int) (match bool) {
+			return val > 1000
+		}).FindFirst()
+		assert.NoError(t, err)
+		assert.Equal(t, 1024, *first)
+		first, err = IntIterate(1, func(prev int) int {
+			return prev + 1
+		}).Parallel().FindFirst()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, *first)
+
+		// the index of a panic in a later batch is its index in the stream
+		boom := panicAt(50000)
+		err = Iterate(0, nil, inc).Parallel().Map(func(src interface{}) (dest interface{}) {
+			boom(src.(int))
+			return src
+		}).Limit(60000).Collect(&res)
+		assertPanicError(t, err, "Map", 50000)
+
+		failed := errors.New("failed")
+		err = Iterate(0, func(val interface{}) bool {
+			return val.(int) < 50000
+		}, inc).Parallel().WithErrorMode(CollectAllErrors).MapE(func(src interface{}) (dest interface{}, err error) {
+			if src.(int)%1000 == 0 {
+				return nil, failed
+			}
+			return src, nil
+		}).Collect(&res)
+		var m *MultiError
+		if assert.True(t, errors.As(err, &m)) {
+			assert.Len(t, m.Errors, 50)
+		}
+	})
+
+	t.Run("test float64", func(t *testing.T) {
+		res, err := Float64Iterate(1, func(prev float64) float64 {
+			return prev / 2
+		}).Limit(3).Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []float64{1, 0.5, 0.25}, res)
+	})
+
+	t.Run("test panic", func(t *testing.T) {
+		var res []int
+		err := Iterate(1, nil, func(prev interface{}) interface{} {
+			if prev.(int) == 4 {
+				panic("boom")
+			}
+			return prev.(int) * 2
+		}).Limit(10).Collect(&res)
+		var p *PanicError
+		if assert.True(t, errors.As(err, &p)) {
+			assert.Equal(t, "Iterate", p.Op)
+			assert.Equal(t, 3, p.Index)
+		}
+	})
+}
+
+func TestRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream IntStream
+		expect []int
+	}{
+		{"test range", IntRange(1, 5), []int{1, 2, 3, 4}},
+		{"test range empty", IntRange(5, 5), []int{}},
+		{"test range reversed", IntRange(5, 1), []int{}},
+		{"test range closed", IntRangeClosed(1, 5), []int{1, 2, 3, 4, 5}},
+		{"test range closed single", IntRangeClosed(5, 5), []int{5}},
+		{"test range closed reversed", IntRangeClosed(5, 1), []int{}},
+		{"test range closed max", IntRangeClosed(math.MaxInt-2, math.MaxInt), []int{math.MaxInt - 2, math.MaxInt - 1, math.MaxInt}},
+		{"test range large limit", IntRange(0, math.MaxInt).Limit(3), []int{0, 1, 2}},
+		{"test range parallel", IntRange(0, 10).Parallel().Map(func(src int) (dest int) {
+			return src * src
+		}).Skip(7), []int{49, 64, 81}},
+		{"test range sorted", IntRange(0, 5).Map(func(src int) (dest int) {
+			return -src
+		}).Sorted(), []int{-4, -3, -2, -1, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tt.stream.Collect()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expect, res)
+		})
+	}
+
+	t.Run("test aggregation", func(t *testing.T) {
+		sum, err := IntRangeClosed(1, 100).Sum()
+		assert.NoError(t, err)
+		assert.Equal(t, 5050, sum)
+
+		avg, err := IntRangeClosed(1, 100).Parallel().Average()
+		assert.NoError(t, err)
+		assert.Equal(t, 50.5, *avg)
+
+		min, err := NumberRangeClosed[uint8](250, 255).Min()
+		assert.NoError(t, err)
+		assert.Equal(t, uint8(250), *min)
+	})
+
+	t.Run("test map to other streams", func(t *testing.T) {
+		res, err := IntRange(0, 3).MapToFloat64(func(src int) (dest float64) {
+			return float64(src) / 2
+		}).Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []float64{0, 0.5, 1}, res)
+
+		var strs []string
+		err = IntRange(0, 3).MapToObj(func(src int) (dest interface{}) {
+			return strconv.Itoa(src)
+		}).Collect(&strs)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"0", "1", "2"}, strs)
+	})
+
+	t.Run("test flat map stays lazy", func(t *testing.T) {
+		res, err := IntRange(1, math.MaxInt).FlatMap(func(val int) IntStream {
+			return IntRange(0, val)
+		}).Limit(6).Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []int{0, 0, 1, 0, 1, 2}, res)
+	})
+
+	t.Run("test take while", func(t *testing.T) {
+		for _, s := range []IntStream{IntRange(0, math.MaxInt), IntRange(0, math.MaxInt).Parallel()} {
+			res, err := s.TakeWhile(func(val int) (match bool) {
+				return val < 5
+			}).Collect()
+			assert.NoError(t, err)
+			assert.Equal(t, []int{0, 1, 2, 3, 4}, res)
+		}
+
+		res, err := IntIterate(1, func(prev int) int {
+			return prev * 2
+		}).DropWhile(func(val int) (match bool) {
+			return val < 10
+		}).TakeWhile(func(val int) (match bool) {
+			return val < 100
+		}).Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []int{16, 32, 64}, res)
+	})
+
+	t.Run("test negative limit and skip", func(t *testing.T) {
+		_, err := IntRange(0, 3).Limit(-1).Collect()
+		assert.EqualError(t, err, "limit error, maxSize less than 0: -1")
+		_, err = IntRange(0, 3).Skip(-1).Collect()
+		assert.EqualError(t, err, "skip error, skipN less than 0: -1")
+	})
+}
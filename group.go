@@ -0,0 +1,367 @@
+package gostream
+
+import "context"
+
+// GroupedStream is a stream whose elements are grouped by their keys, the groups are aggregated into rows by
+// Aggregate.
+type GroupedStream interface {
+	BaseStream
+	// Aggregate returns an AggregatedStream consisting of a Row per key, in the order the keys first appear in the
+	// stream. The values of a row are the results of aggregators applied to the elements of the group, in the order
+	// of aggregators. A parallel stream splits the elements into parts, groups and aggregates the parts concurrently,
+	// and then merges the partial results of each group in encounter order.
+	// The aggregators are Count, SumInt, SumFloat64, AvgFloat64, MinOf, MaxOf, or the ones made by NewAggregator. The
+	// aggregators of the minimum and maximum are named MinOf and MaxOf, since MinBy and MaxBy are already the functions
+	// finding them in a whole Stream.
+	Aggregate(aggregators ...Aggregator) AggregatedStream
+}
+
+// AggregatedStream is the stream of the rows aggregated by a GroupedStream.
+type AggregatedStream interface {
+	Stream
+	// Having returns an AggregatedStream consisting of the rows of this stream matching predicate.
+	Having(predicate func(row Row) (match bool)) AggregatedStream
+}
+
+// Row is the result of aggregating a group, Values are the results of the aggregators in the order they were passed
+// to Aggregate.
+type Row struct {
+	Key    interface{}
+	Values []interface{}
+}
+
+// Aggregator computes a value of each group aggregated by a GroupedStream. The parts of a group are aggregated
+// separately by a parallel stream, and the partial results are merged.
+type Aggregator struct {
+	// partial aggregates the elements of a part of a group.
+	partial func(part Stream) (interface{}, error)
+	// merge merges the partial result b, which is of the elements encountered after the ones of a, into a.
+	merge func(a, b interface{}) interface{}
+	// finish transforms the merged partial result into the value of the row, the partial result is the value if it's
+	// nil.
+	finish func(partial interface{}) (interface{}, error)
+}
+
+// groupedStream groups the elements of s by key.
+type groupedStream struct {
+	s   groupable
+	key func(val interface{}) interface{}
+}
+
+// groupable is implemented by the streams whose elements can be grouped.
+type groupable interface {
+	Stream
+	aggregate(key func(val interface{}) interface{}, aggregators []Aggregator) Stream
+}
+
+type aggregatedStream struct {
+	Stream
+}
+
+// NewAggregator returns an Aggregator described by the given functions. partial aggregates the elements of a part of
+// a group, which are the whole group if the stream is sequential. merge merges the partial result b, which is of the
+// elements encountered after the ones of a, into a. finish transforms the merged partial result into the value of the
+// row, the partial result is the value if finish is nil.
+func NewAggregator(
+	partial func(part Stream) (interface{}, error),
+	merge func(a, b interface{}) interface{},
+	finish func(partial interface{}) interface{},
+) Aggregator {
+	a := Aggregator{partial: partial, merge: merge}
+	if finish != nil {
+		a.finish = func(partial interface{}) (interface{}, error) {
+			return finish(partial), nil
+		}
+	}
+	return a
+}
+
+// group is a group of the elements with the same key, indices are the indices of the elements in the stream, and
+// partials are the partial results of the aggregators.
+type group struct {
+	key      interface{}
+	elements []*element
+	indices  []int
+	partials []interface{}
+}
+
+// Count returns an Aggregator counting the elements of a group, the value is an int.
+func Count() Aggregator {
+	return NewAggregator(
+		func(part Stream) (interface{}, error) {
+			return part.Count()
+		},
+		func(a, b interface{}) interface{} {
+			return a.(int) + b.(int)
+		},
+		nil,
+	)
+}
+
+// SumInt returns an Aggregator summing the results of applying mapper to the elements of a group, the value is an
+// int. The parts of a group are summed without overflow and merged, an error wrapping ErrOverflow is returned if the
+// sum of a group is out of the range of int, like SumChecked.
+func SumInt(mapper func(val interface{}) int) Aggregator {
+	return Aggregator{
+		partial: func(part Stream) (interface{}, error) {
+			ints, err := part.MapToInt(mapper).Collect()
+			if err != nil {
+				return nil, err
+			}
+			return summate(ints), nil
+		},
+		merge: func(a, b interface{}) interface{} {
+			a.(*summation[int]).merge(b.(*summation[int]))
+			return a
+		},
+		finish: func(partial interface{}) (interface{}, error) {
+			return partial.(*summation[int]).checked()
+		},
+	}
+}
+
+// SumFloat64 returns an Aggregator summing the results of applying mapper to the elements of a group, the value is a
+// float64.
+func SumFloat64(mapper func(val interface{}) float64) Aggregator {
+	return NewAggregator(
+		func(part Stream) (interface{}, error) {
+			return part.MapToFloat64(mapper).SummaryStatistics()
+		},
+		combineStatistics,
+		func(partial interface{}) interface{} {
+			return partial.(*Float64SummaryStatistics).Sum()
+		},
+	)
+}
+
+// AvgFloat64 returns an Aggregator averaging the results of applying mapper to the elements of a group, the value is
+// a float64.
+func AvgFloat64(mapper func(val interface{}) float64) Aggregator {
+	return NewAggregator(
+		func(part Stream) (interface{}, error) {
+			return part.MapToFloat64(mapper).SummaryStatistics()
+		},
+		combineStatistics,
+		func(partial interface{}) interface{} {
+			// the compensated sum is more accurate than the running mean of the statistics
+			statistics := partial.(*Float64SummaryStatistics)
+			return statistics.Sum() / float64(statistics.Count())
+		},
+	)
+}
+
+// MinOf returns an Aggregator of the element of a group whose key is the minimum, it's the MinBy of each group. The
+// first one in encounter order is the value if several elements have the minimum key.
+func MinOf[K Ordered](key func(val interface{}) K) Aggregator {
+	return NewAggregator(
+		func(part Stream) (interface{}, error) {
+			return MinBy(part, key)
+		},
+		func(a, b interface{}) interface{} {
+			if compareOrdered(key(b), key(a)) < 0 {
+				return b
+			}
+			return a
+		},
+		nil,
+	)
+}
+
+// MaxOf returns an Aggregator of the element of a group whose key is the maximum, it's the MaxBy of each group. The
+// first one in encounter order is the value if several elements have the maximum key.
+func MaxOf[K Ordered](key func(val interface{}) K) Aggregator {
+	return NewAggregator(
+		func(part Stream) (interface{}, error) {
+			return MaxBy(part, key)
+		},
+		func(a, b interface{}) interface{} {
+			if compareOrdered(key(b), key(a)) > 0 {
+				return b
+			}
+			return a
+		},
+		nil,
+	)
+}
+
+func (s *sequentialStream) GroupBy(key func(val interface{}) interface{}) GroupedStream {
+	return &groupedStream{s: s, key: key}
+}
+
+func (s *sequentialStream) aggregate(key func(val interface{}) interface{}, aggregators []Aggregator) Stream {
+	return newSequentialStream(s.config, barrierStage(s.tail, func(ctx context.Context, elements []*element) ([]*element, error) {
+		return aggregateRows(ctx, 1, key, aggregators, elements)
+	}))
+}
+
+func (p *parallelStream) GroupBy(key func(val interface{}) interface{}) GroupedStream {
+	return &groupedStream{s: p, key: key}
+}
+
+func (p *parallelStream) aggregate(key func(val interface{}) interface{}, aggregators []Aggregator) Stream {
+	return newParallelStream(p.config, barrierStage(p.tail, func(ctx context.Context, elements []*element) ([]*element, error) {
+		return aggregateRows(ctx, p.parallelism, key, aggregators, elements)
+	}))
+}
+
+func (e *errStream) GroupBy(key func(val interface{}) interface{}) GroupedStream {
+	return &groupedStream{s: e, key: key}
+}
+
+func (e *errStream) aggregate(func(val interface{}) interface{}, []Aggregator) Stream {
+	return e
+}
+
+func (g *groupedStream) IsParallel() bool {
+	return g.s.IsParallel()
+}
+
+func (g *groupedStream) Err() error {
+	return g.s.Err()
+}
+
+func (g *groupedStream) Aggregate(aggregators ...Aggregator) AggregatedStream {
+	return &aggregatedStream{g.s.aggregate(g.key, aggregators)}
+}
+
+func (a *aggregatedStream) Having(predicate func(row Row) (match bool)) AggregatedStream {
+	return &aggregatedStream{a.Filter(func(val interface{}) (match bool) {
+		return predicate(val.(Row))
+	})}
+}
+
+// aggregateRows groups elements by key and aggregates each group into a Row. The elements are split into
+// batchSize(parallelism) parts, which are grouped and aggregated concurrently, and then the partial results of each
+// group are merged in encounter order.
+func aggregateRows(ctx context.Context, parallelism int, key func(val interface{}) interface{}, aggregators []Aggregator,
+	elements []*element) ([]*element, error) {
+	n := batchSize(parallelism)
+	if n > len(elements) {
+		n = len(elements)
+	}
+	parts := make([][]*group, n)
+	errs := make([]error, n)
+	each := func(i int) {
+		start, end := i*len(elements)/n, (i+1)*len(elements)/n
+		parts[i], errs[i] = aggregatePart(ctx, start, key, aggregators, elements[start:end])
+	}
+	var err error
+	if n == 1 {
+		err = sequentialEach(ctx, "GroupBy", n, each)
+	} else {
+		err = parallelEach(ctx, "GroupBy", parallelism, n, each)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	merged := make([]*group, 0)
+	index := make(map[interface{}]*group)
+	err = guard("Aggregate", -1, func() {
+		for _, part := range parts {
+			for _, g := range part {
+				m, ok := index[g.key]
+				if !ok {
+					index[g.key] = g
+					merged = append(merged, g)
+					continue
+				}
+				for i, aggregator := range aggregators {
+					m.partials[i] = aggregator.merge(m.partials[i], g.partials[i])
+				}
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]*element, len(merged))
+	var finishErr error
+	err = guard("Aggregate", -1, func() {
+		for i, g := range merged {
+			values := make([]interface{}, len(aggregators))
+			for j, aggregator := range aggregators {
+				values[j] = g.partials[j]
+				if aggregator.finish == nil {
+					continue
+				}
+				if values[j], finishErr = aggregator.finish(values[j]); finishErr != nil {
+					return
+				}
+			}
+			rows[i] = newElement(Row{Key: g.key, Values: values})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if finishErr != nil {
+		return nil, finishErr
+	}
+	return rows, nil
+}
+
+// aggregatePart groups the elements of a part starting at offset by key, and aggregates each group, the groups are
+// returned in the order their keys first appear.
+func aggregatePart(ctx context.Context, offset int, key func(val interface{}) interface{}, aggregators []Aggregator,
+	elements []*element) ([]*group, error) {
+	groups := make([]*group, 0)
+	index := make(map[interface{}]*group)
+	for i, e := range elements {
+		var k interface{}
+		err := guard("GroupBy", offset+i, func() {
+			k = key(e.data)
+			if _, ok := index[k]; !ok {
+				index[k] = &group{key: k}
+				groups = append(groups, index[k])
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+		index[k].elements = append(index[k].elements, e)
+		index[k].indices = append(index[k].indices, offset+i)
+	}
+	for _, g := range groups {
+		g.partials = make([]interface{}, len(aggregators))
+		for i, aggregator := range aggregators {
+			partial, err := g.aggregate(ctx, aggregator)
+			if err != nil {
+				return nil, err
+			}
+			g.partials[i] = partial
+		}
+	}
+	return groups, nil
+}
+
+// aggregate returns the partial result of aggregator applied to the elements of g. The panic of aggregator is
+// reported as the *PanicError of Aggregate, whose index is the index of the element in the stream rather than its
+// index in the group.
+func (g *group) aggregate(ctx context.Context, aggregator Aggregator) (partial interface{}, err error) {
+	panicErr := guard("Aggregate", -1, func() {
+		partial, err = aggregator.partial(newSequentialStream(config{ctx: ctx}, sliceStage(g.elements)))
+	})
+	if panicErr != nil {
+		err = panicErr
+	}
+	p, ok := err.(*PanicError)
+	if !ok {
+		return partial, err
+	}
+	index := -1
+	if p.Index >= 0 && p.Index < len(g.indices) {
+		index = g.indices[p.Index]
+	}
+	return nil, &PanicError{Op: "Aggregate", Index: index, Value: p.Value, Stack: p.Stack}
+}
+
+func combineStatistics(a, b interface{}) interface{} {
+	a.(*Float64SummaryStatistics).Combine(b.(*Float64SummaryStatistics))
+	return a
+}
@@ -0,0 +1,145 @@
+package gostream
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+type testSale struct {
+	region string
+	amount int
+	price  float64
+}
+
+func TestStreamGroupBy(t *testing.T) {
+	regions := []string{"north", "south", "east"}
+	sales := make([]testSale, 999)
+	for i := range sales {
+		sales[i] = testSale{regions[i%3], i, float64(i%10) / 2}
+	}
+	region := func(val interface{}) interface{} {
+		return val.(testSale).region
+	}
+	amount := func(val interface{}) int {
+		return val.(testSale).amount
+	}
+	price := func(val interface{}) float64 {
+		return val.(testSale).price
+	}
+	for _, s := range []Stream{NewSequentialStream(sales), NewParallelStream(sales).WithParallelism(4)} {
+		var rows []Row
+		err := s.GroupBy(region).Aggregate(Count(), SumInt(amount), SumFloat64(price), AvgFloat64(price),
+			MinOf(price), MaxOf(amount)).Collect(&rows)
+		assert.NoError(t, err)
+		if assert.Len(t, rows, 3) {
+			assert.Equal(t, Row{"north", []interface{}{333, 165834, 747.0, 2.2432432432432434, sales[0], sales[996]}}, rows[0])
+			assert.Equal(t, Row{"south", []interface{}{333, 166167, 748.5, 2.2477477477477477, sales[10], sales[997]}}, rows[1])
+			assert.Equal(t, "east", rows[2].Key)
+			assert.Equal(t, 166500, rows[2].Values[1])
+		}
+
+		// the last amount of a group, so the partial results must be merged in encounter order
+		last := NewAggregator(func(part Stream) (interface{}, error) {
+			return part.MapToInt(amount).Collect()
+		}, func(a, b interface{}) interface{} {
+			return append(a.([]int), b.([]int)...)
+		}, func(partial interface{}) interface{} {
+			amounts := partial.([]int)
+			return amounts[len(amounts)-1]
+		})
+		rows = nil
+		err = s.GroupBy(region).Aggregate(last).Collect(&rows)
+		assert.NoError(t, err)
+		assert.Equal(t, []Row{{"north", []interface{}{996}}, {"south", []interface{}{997}}, {"east", []interface{}{998}}}, rows)
+
+		rows = nil
+		err = s.GroupBy(region).Aggregate(SumInt(amount)).Having(func(row Row) (match bool) {
+			return row.Values[0].(int) > 166000
+		}).Collect(&rows)
+		assert.NoError(t, err)
+		assert.Equal(t, []Row{{"south", []interface{}{166167}}, {"east", []interface{}{166500}}}, rows)
+
+		count, err := s.Filter(func(val interface{}) (match bool) {
+			return false
+		}).GroupBy(region).Aggregate(Count()).Count()
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+
+		g := s.GroupBy(region)
+		assert.Equal(t, s.IsParallel(), g.IsParallel())
+		assert.NoError(t, g.Err())
+		assert.Equal(t, s.IsParallel(), g.Aggregate().IsParallel())
+	}
+}
+
+func TestStreamGroupByError(t *testing.T) {
+	boom := panicAt(2)
+	ints := make([]int, 100)
+	for i := range ints {
+		ints[i] = i
+	}
+	for _, s := range []Stream{NewSequentialStream(ints), NewParallelStream(ints).WithParallelism(4)} {
+		_, err := s.GroupBy(func(val interface{}) interface{} {
+			boom(val.(int))
+			return val
+		}).Aggregate(Count()).Count()
+		assertPanicError(t, err, "GroupBy", 2)
+
+		_, err = s.GroupBy(func(val interface{}) interface{} {
+			return []int{val.(int)}
+		}).Aggregate(Count()).Count()
+		var p *PanicError
+		if assert.True(t, errors.As(err, &p)) {
+			assert.Equal(t, "GroupBy", p.Op)
+		}
+
+		// the index of 37 in its group is 18, the index in the stream is reported
+		mapperBoom := panicAt(37)
+		_, err = s.GroupBy(func(val interface{}) interface{} {
+			return val.(int) % 2
+		}).Aggregate(Count(), SumInt(func(val interface{}) int {
+			mapperBoom(val.(int))
+			return val.(int)
+		})).Count()
+		assertPanicError(t, err, "Aggregate", 37)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = s.WithContext(ctx).GroupBy(func(val interface{}) interface{} {
+			return val
+		}).Aggregate(Count()).Count()
+		assert.Equal(t, context.Canceled, err)
+	}
+
+	// the partial sums overflow when merged, but the sum of the group doesn't unless 5 is summed
+	large := []int{math.MaxInt, math.MaxInt, -math.MaxInt, 5}
+	for _, s := range []Stream{NewSequentialStream(large), NewParallelStream(large).WithParallelism(4)} {
+		all := func(val interface{}) interface{} {
+			return "all"
+		}
+		identity := func(val interface{}) int {
+			return val.(int)
+		}
+		_, err := s.GroupBy(all).Aggregate(SumInt(identity)).Count()
+		assert.True(t, errors.Is(err, ErrOverflow))
+		var rows []Row
+		err = s.Filter(func(val interface{}) (match bool) {
+			return val.(int) != 5
+		}).GroupBy(all).Aggregate(SumInt(identity)).Collect(&rows)
+		assert.NoError(t, err)
+		assert.Equal(t, []Row{{"all", []interface{}{math.MaxInt}}}, rows)
+	}
+
+	g := testErrStream.GroupBy(func(val interface{}) interface{} {
+		return val
+	})
+	assert.Error(t, g.Err())
+	assert.False(t, g.IsParallel())
+	_, err := g.Aggregate(Count()).Having(func(row Row) (match bool) {
+		return true
+	}).Count()
+	assert.Error(t, err)
+}
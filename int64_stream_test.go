@@ -0,0 +1,42 @@
+package gostream
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+func TestMapToInt64(t *testing.T) {
+	for _, s := range []IntStream{NewSequentialIntStream([]int{1, 2}), NewParallelIntStream([]int{1, 2}),
+		IntRangeClosed(1, 2)} {
+		res, err := s.MapToInt64(func(src int) (dest int64) {
+			return int64(src) << 40
+		}).Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{1 << 40, 2 << 40}, res)
+	}
+
+	for _, newStream := range []func([]*element) Stream{newSequentialStreamForTest, newParallelStreamForTest} {
+		s := newStream(intSliceToElements([]int{1, 2})).MapToInt64(func(src interface{}) (dest int64) {
+			return int64(src.(int))
+		})
+		res, err := s.Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{1, 2}, res)
+		assert.Equal(t, newStream(nil).IsParallel(), s.IsParallel())
+	}
+
+	ids, err := NewSequentialStringStream([]string{"7", "42"}).MapToInt64(func(src string) (dest int64) {
+		id, _ := strconv.ParseInt(src, 10, 64)
+		return id
+	}).Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{7, 42}, ids)
+
+	assert.Error(t, testErrStream.MapToInt64(func(src interface{}) (dest int64) {
+		return 0
+	}).Err())
+	assert.Error(t, testErrIntStream.MapToInt64(func(src int) (dest int64) {
+		return 0
+	}).Err())
+}
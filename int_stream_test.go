@@ -1,9 +1,12 @@
 package gostream
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"math"
+	"sync/atomic"
 	"testing"
 )
 
@@ -27,25 +30,44 @@ func TestConcatIntStream(t *testing.T) {
 		assert.Len(t, res, 0)
 	})
 	t.Run("test a empty", func(t *testing.T) {
-		res, err := ConcatIntStream(&sequentialIntStream{}, &sequentialIntStream{[]int{1}}).Collect()
+		res, err := ConcatIntStream(&sequentialIntStream{}, &sequentialIntStream{elements: []int{1}}).Collect()
 		assert.NoError(t, err)
 		expect := []int{1}
 		assertSliceEquals(t, expect, res)
 	})
 	t.Run("test b empty", func(t *testing.T) {
-		res, err := ConcatIntStream(&sequentialIntStream{[]int{1}}, &sequentialIntStream{}).Collect()
+		res, err := ConcatIntStream(&sequentialIntStream{elements: []int{1}}, &sequentialIntStream{}).Collect()
 		assert.NoError(t, err)
 		expect := []int{1}
 		assertSliceEquals(t, expect, res)
 	})
 	t.Run("test normal", func(t *testing.T) {
-		res, err := ConcatIntStream(&sequentialIntStream{[]int{1}}, &sequentialIntStream{[]int{2}}).Collect()
+		res, err := ConcatIntStream(&sequentialIntStream{elements: []int{1}}, &sequentialIntStream{elements: []int{2}}).Collect()
 		assert.NoError(t, err)
 		expect := []int{1, 2}
 		assertSliceEquals(t, expect, res)
 	})
 }
 
+func TestZipInt(t *testing.T) {
+	t.Run("test error", func(t *testing.T) {
+		assert.Error(t, ZipInt(testErrIntStream, NewSequentialIntStream([]int{1}), nil).Err())
+		assert.Error(t, ZipInt(NewSequentialIntStream([]int{1}), testErrIntStream, nil).Err())
+	})
+	t.Run("test normal", func(t *testing.T) {
+		s := ZipInt(NewParallelIntStream([]int{1, 2, 3}), IntRange(10, math.MaxInt), func(first, second int) int {
+			return first * second
+		})
+		assert.False(t, s.IsParallel())
+		res, err := s.Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []int{10, 22, 36}, res)
+		sum, err := s.Sum()
+		assert.NoError(t, err)
+		assert.Equal(t, 68, sum)
+	})
+}
+
 func Test_errIntStream_Average(t *testing.T) {
 	ans, err := testErrIntStream.Average()
 	assert.Nil(t, ans)
@@ -157,6 +179,12 @@ func Test_errIntStream_Sequential(t *testing.T) {
 	})
 }
 
+func Test_errIntStream_TakeWhile(t *testing.T) {
+	at := assert.New(t)
+	at.Error(testErrIntStream.TakeWhile(nil).Err())
+	at.Error(testErrIntStream.DropWhile(nil).Err())
+}
+
 func Test_errIntStream_Skip(t *testing.T) {
 	at := assert.New(t)
 	s := testErrIntStream.Skip(1)
@@ -246,7 +274,11 @@ func Test_sequentialIntStream_Sequential(t *testing.T) {
 }
 
 func Test_sequentialIntStream_Skip(t *testing.T) {
-	testIntStreamSkip(t, func(ints []int) IntStream { return &sequentialIntStream{ints} })
+	testIntStreamSkip(t, func(ints []int) IntStream { return &sequentialIntStream{elements: ints} })
+}
+
+func Test_sequentialIntStream_TakeWhile(t *testing.T) {
+	testIntStreamTakeWhile(t, NewSequentialIntStream)
 }
 
 func Test_sequentialIntStream_Sorted(t *testing.T) {
@@ -321,6 +353,12 @@ func Test_parallelIntStream_Sequential(t *testing.T) {
 	testIntStreamSequential(t, NewParallelIntStream)
 }
 
+func Test_parallelIntStream_TakeWhile(t *testing.T) {
+	testIntStreamTakeWhile(t, func(ints []int) IntStream {
+		return NewParallelIntStream(ints).WithParallelism(3)
+	})
+}
+
 func Test_parallelIntStream_Skip(t *testing.T) {
 	testIntStreamSkip(t, NewParallelIntStream)
 }
@@ -833,6 +871,65 @@ func testIntStreamSkip(t *testing.T, stream func([]int) IntStream) {
 	}
 }
 
+func testIntStreamTakeWhile(t *testing.T, stream func([]int) IntStream) {
+	lessThan3 := func(val int) (match bool) {
+		return val < 3
+	}
+	tests := []struct {
+		name       string
+		elements   []int
+		expectTake []int
+		expectDrop []int
+	}{
+		{"test empty", []int{}, []int{}, []int{}},
+		{"test all match", []int{0, 1, 2}, []int{0, 1, 2}, []int{}},
+		{"test none match", []int{3, 1, 2}, []int{}, []int{3, 1, 2}},
+		{"test prefix match", []int{1, 2, 1, 0, 3, 1, 2}, []int{1, 2, 1, 0}, []int{3, 1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := stream(tt.elements)
+			parallel := s.IsParallel()
+			take := s.TakeWhile(lessThan3)
+			assert.Equal(t, parallel, take.IsParallel())
+			res, err := take.Collect()
+			assert.NoError(t, err)
+			assertSliceEquals(t, tt.expectTake, res)
+
+			drop := s.DropWhile(lessThan3)
+			assert.Equal(t, parallel, drop.IsParallel())
+			res, err = drop.Collect()
+			assert.NoError(t, err)
+			assertSliceEquals(t, tt.expectDrop, res)
+		})
+	}
+
+	t.Run("test stop early", func(t *testing.T) {
+		var tested int64
+		res, err := stream([]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}).TakeWhile(func(val int) (match bool) {
+			atomic.AddInt64(&tested, 1)
+			return val < 2
+		}).Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1}, res)
+		assert.LessOrEqual(t, atomic.LoadInt64(&tested), int64(3))
+	})
+
+	t.Run("test panic", func(t *testing.T) {
+		err := stream([]int{1, 2, 3, 4, 5}).DropWhile(func(val int) (match bool) {
+			if val == 5 {
+				panic("boom")
+			}
+			return true
+		}).Err()
+		var p *PanicError
+		if assert.True(t, errors.As(err, &p)) {
+			assert.Equal(t, "DropWhile", p.Op)
+			assert.Equal(t, 4, p.Index)
+		}
+	})
+}
+
 func intPtr(i int) *int {
 	return &i
 }
@@ -888,3 +985,33 @@ func Test_errIntStream_MapToFloat64(t *testing.T) {
 		assert.True(t, s.IsParallel())
 	})
 }
+
+func TestIntStreamWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, s := range []IntStream{NewSequentialIntStream([]int{1, 2, 3}), NewParallelIntStream([]int{1, 2, 3})} {
+		s = s.WithContext(ctx).Map(func(src int) (dest int) {
+			cancel()
+			return src
+		})
+		assert.Equal(t, context.Canceled, s.Err())
+		res, err := s.Collect()
+		assert.Nil(t, res)
+		assert.Equal(t, context.Canceled, err)
+		_, err = s.Max()
+		assert.Error(t, err)
+	}
+	assert.Same(t, testErrIntStream, testErrIntStream.WithContext(ctx))
+}
+
+func TestIntStreamWithParallelism(t *testing.T) {
+	for _, s := range []IntStream{NewSequentialIntStream([]int{1, 2, 3}), NewParallelIntStream([]int{1, 2, 3})} {
+		res, err := s.WithParallelism(2).Map(func(src int) (dest int) {
+			return src * 2
+		}).Collect()
+		assert.NoError(t, err)
+		assertSliceEquals(t, []int{2, 4, 6}, res)
+		assert.Error(t, s.WithParallelism(-1).Err())
+	}
+	assert.Same(t, testErrIntStream, testErrIntStream.WithParallelism(2))
+}
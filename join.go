@@ -0,0 +1,322 @@
+package gostream
+
+import "context"
+
+// JoinKeys describes how a join matches the elements of a stream with the elements of the other stream.
+type JoinKeys struct {
+	// Key returns the key of an element of the stream.
+	Key func(val interface{}) interface{}
+	// OtherKey returns the key of an element of the other stream.
+	OtherKey func(val interface{}) interface{}
+	// Hashcode returns the hashcode of key, 2 equal keys should return the same hashcode. The key itself is the
+	// hashcode if it's nil, so the keys should be comparable then.
+	Hashcode func(key interface{}) interface{}
+	// Equals returns true if the keys a and b are equal, otherwise returns false. The keys are compared by == if it's
+	// nil.
+	Equals func(a, b interface{}) bool
+}
+
+type joinKind int
+
+const (
+	innerJoin joinKind = iota
+	leftJoin
+	fullOuterJoin
+	semiJoin
+	antiJoin
+)
+
+// joiner joins the elements of a stream with the hash table built from the other stream, op is the name of the
+// operation reported when a function of the join panics.
+type joiner struct {
+	op     string
+	kind   joinKind
+	keys   JoinKeys
+	result func(val, other interface{}) interface{}
+	table  *hashTable
+}
+
+// hashTable is the build side of a hash join, it indexes the elements of the other stream by the hashcode of their
+// keys.
+type hashTable struct {
+	keys     JoinKeys
+	elements []*element
+	// otherKeys are the keys of the elements.
+	otherKeys []interface{}
+	buckets   map[interface{}][]int
+}
+
+// joinIterator produces the joined elements of its upstream one at a time, the hash table is built from the other
+// stream when the first element is pulled.
+type joinIterator struct {
+	iterator
+	joiner
+	other   Stream
+	pending []*element
+	// matched marks the elements of the other stream matched by any upstream element, it's used by the full outer
+	// join only.
+	matched []bool
+	index   int
+	flushed bool
+	e       error
+}
+
+func (s *sequentialStream) Join(other Stream, keys JoinKeys, result func(val, other interface{}) interface{}) Stream {
+	return s.join(other, joiner{op: "Join", kind: innerJoin, keys: keys, result: result})
+}
+
+func (s *sequentialStream) LeftJoin(other Stream, keys JoinKeys, result func(val, other interface{}) interface{}) Stream {
+	return s.join(other, joiner{op: "LeftJoin", kind: leftJoin, keys: keys, result: result})
+}
+
+func (s *sequentialStream) FullOuterJoin(other Stream, keys JoinKeys, result func(val, other interface{}) interface{}) Stream {
+	return s.join(other, joiner{op: "FullOuterJoin", kind: fullOuterJoin, keys: keys, result: result})
+}
+
+func (s *sequentialStream) SemiJoin(other Stream, keys JoinKeys) Stream {
+	return s.join(other, joiner{op: "SemiJoin", kind: semiJoin, keys: keys})
+}
+
+func (s *sequentialStream) AntiJoin(other Stream, keys JoinKeys) Stream {
+	return s.join(other, joiner{op: "AntiJoin", kind: antiJoin, keys: keys})
+}
+
+func (s *sequentialStream) join(other Stream, j joiner) Stream {
+	if s.tail == nil && j.kind != fullOuterJoin {
+		return s
+	}
+	return newSequentialStream(s.config, joinStage(s.tail, other, j))
+}
+
+// Join of a parallel stream builds the hash table from the other stream, and then probes it with the elements of the
+// stream concurrently. The joined elements are still in encounter order.
+func (p *parallelStream) Join(other Stream, keys JoinKeys, result func(val, other interface{}) interface{}) Stream {
+	return p.join(other, joiner{op: "Join", kind: innerJoin, keys: keys, result: result})
+}
+
+func (p *parallelStream) LeftJoin(other Stream, keys JoinKeys, result func(val, other interface{}) interface{}) Stream {
+	return p.join(other, joiner{op: "LeftJoin", kind: leftJoin, keys: keys, result: result})
+}
+
+func (p *parallelStream) FullOuterJoin(other Stream, keys JoinKeys, result func(val, other interface{}) interface{}) Stream {
+	return p.join(other, joiner{op: "FullOuterJoin", kind: fullOuterJoin, keys: keys, result: result})
+}
+
+func (p *parallelStream) SemiJoin(other Stream, keys JoinKeys) Stream {
+	return p.join(other, joiner{op: "SemiJoin", kind: semiJoin, keys: keys})
+}
+
+func (p *parallelStream) AntiJoin(other Stream, keys JoinKeys) Stream {
+	return p.join(other, joiner{op: "AntiJoin", kind: antiJoin, keys: keys})
+}
+
+func (p *parallelStream) join(other Stream, j joiner) Stream {
+	if p.tail == nil && j.kind != fullOuterJoin {
+		return p
+	}
+	return newParallelStream(p.config, barrierStage(p.tail, func(ctx context.Context, elements []*element) ([]*element, error) {
+		table, err := buildHashTable(j.op, j.keys, other)
+		if err != nil {
+			return nil, err
+		}
+		// the stage may be opened by several terminal operations, so the joiner of the stream is left untouched
+		j := j
+		j.table = table
+		results := make([][]*element, len(elements))
+		matches := make([][]int, len(elements))
+		err = parallelEach(ctx, j.op, p.parallelism, len(elements), func(i int) {
+			results[i], matches[i] = j.probe(elements[i])
+		})
+		if err != nil {
+			return nil, err
+		}
+		joined := make([]*element, 0, len(elements))
+		for _, r := range results {
+			joined = append(joined, r...)
+		}
+		if j.kind != fullOuterJoin {
+			return joined, nil
+		}
+		matched := make([]bool, len(table.elements))
+		for _, m := range matches {
+			for _, i := range m {
+				matched[i] = true
+			}
+		}
+		unmatched, err := j.unmatched(matched)
+		if err != nil {
+			return nil, err
+		}
+		return append(joined, unmatched...), nil
+	}))
+}
+
+func (e *errStream) Join(Stream, JoinKeys, func(val, other interface{}) interface{}) Stream {
+	return e
+}
+
+func (e *errStream) LeftJoin(Stream, JoinKeys, func(val, other interface{}) interface{}) Stream {
+	return e
+}
+
+func (e *errStream) FullOuterJoin(Stream, JoinKeys, func(val, other interface{}) interface{}) Stream {
+	return e
+}
+
+func (e *errStream) SemiJoin(Stream, JoinKeys) Stream {
+	return e
+}
+
+func (e *errStream) AntiJoin(Stream, JoinKeys) Stream {
+	return e
+}
+
+func joinStage(upstream stage, other Stream, j joiner) stage {
+	return func(ctx context.Context) iterator {
+		return &joinIterator{iterator: openStage(ctx, upstream), joiner: j, other: other}
+	}
+}
+
+func (j *joinIterator) next() (*element, bool) {
+	for j.e == nil {
+		if len(j.pending) > 0 {
+			e := j.pending[0]
+			j.pending = j.pending[1:]
+			return e, true
+		}
+		if j.table == nil {
+			if j.table, j.e = buildHashTable(j.op, j.keys, j.other); j.e == nil {
+				j.matched = make([]bool, len(j.table.elements))
+			}
+			continue
+		}
+		e, ok := j.iterator.next()
+		if !ok {
+			if j.kind != fullOuterJoin || j.flushed || j.iterator.err() != nil {
+				break
+			}
+			j.flushed = true
+			j.pending, j.e = j.unmatched(j.matched)
+			continue
+		}
+		var matches []int
+		j.e = guard(j.op, j.index, func() {
+			j.pending, matches = j.probe(e)
+		})
+		j.index++
+		for _, i := range matches {
+			j.matched[i] = true
+		}
+	}
+	return nil, false
+}
+
+func (j *joinIterator) err() error {
+	if j.e != nil {
+		return j.e
+	}
+	return j.iterator.err()
+}
+
+// probe returns the elements joined from e, along with the indices of the elements of the other stream e matches if
+// it's a full outer join.
+func (j *joiner) probe(e *element) ([]*element, []int) {
+	matches := j.table.lookup(j.keys.Key(e.data))
+	switch j.kind {
+	case semiJoin:
+		if len(matches) > 0 {
+			return []*element{e}, nil
+		}
+		return nil, nil
+	case antiJoin:
+		if len(matches) == 0 {
+			return []*element{e}, nil
+		}
+		return nil, nil
+	}
+	if len(matches) == 0 {
+		if j.kind == innerJoin {
+			return nil, nil
+		}
+		return []*element{newElement(j.result(e.data, nil))}, nil
+	}
+	joined := make([]*element, len(matches))
+	for i, m := range matches {
+		joined[i] = newElement(j.result(e.data, j.table.elements[m].data))
+	}
+	if j.kind != fullOuterJoin {
+		matches = nil
+	}
+	return joined, matches
+}
+
+// unmatched returns the elements joined from the elements of the other stream not matched by any element, in the
+// encounter order of the other stream.
+func (j *joiner) unmatched(matched []bool) ([]*element, error) {
+	joined := make([]*element, 0)
+	err := guard(j.op, -1, func() {
+		for i, e := range j.table.elements {
+			if !matched[i] {
+				joined = append(joined, newElement(j.result(nil, e.data)))
+			}
+		}
+	})
+	return joined, err
+}
+
+// buildHashTable evaluates other and indexes its elements by their keys, the panic of the key functions is returned
+// as the *PanicError of op without index, since the element doesn't belong to the stream joined.
+func buildHashTable(op string, keys JoinKeys, other Stream) (*hashTable, error) {
+	elements, err := drain(iteratorOf(other))
+	if err != nil {
+		return nil, err
+	}
+	t := &hashTable{
+		keys:      keys,
+		elements:  elements,
+		otherKeys: make([]interface{}, len(elements)),
+		buckets:   make(map[interface{}][]int),
+	}
+	err = guard(op, -1, func() {
+		for i, e := range elements {
+			key := keys.OtherKey(e.data)
+			code := t.hashcode(key)
+			t.otherKeys[i] = key
+			t.buckets[code] = append(t.buckets[code], i)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// lookup returns the indices of the elements whose keys are equal to key in encounter order.
+func (t *hashTable) lookup(key interface{}) []int {
+	bucket := t.buckets[t.hashcode(key)]
+	if t.keys.Hashcode == nil && t.keys.Equals == nil {
+		// the keys are the hashcodes, so all the keys in the bucket are equal
+		return bucket
+	}
+	matches := make([]int, 0, len(bucket))
+	for _, i := range bucket {
+		if t.equals(key, t.otherKeys[i]) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+func (t *hashTable) hashcode(key interface{}) interface{} {
+	if t.keys.Hashcode == nil {
+		return key
+	}
+	return t.keys.Hashcode(key)
+}
+
+func (t *hashTable) equals(a, b interface{}) bool {
+	if t.keys.Equals == nil {
+		return a == b
+	}
+	return t.keys.Equals(a, b)
+}
@@ -0,0 +1,163 @@
+package gostream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+type testCustomer struct {
+	id   int
+	name string
+}
+
+type testOrder struct {
+	id         int
+	customerID int
+}
+
+var testOrderKeys = JoinKeys{
+	Key: func(val interface{}) interface{} {
+		return val.(testOrder).customerID
+	},
+	OtherKey: func(val interface{}) interface{} {
+		return val.(testCustomer).id
+	},
+}
+
+func testOrderCustomer(val, other interface{}) interface{} {
+	order, customer := "-", "-"
+	if val != nil {
+		order = fmt.Sprint(val.(testOrder).id)
+	}
+	if other != nil {
+		customer = other.(testCustomer).name
+	}
+	return order + ":" + customer
+}
+
+func TestStreamJoin(t *testing.T) {
+	orders := []testOrder{{1, 10}, {2, 30}, {3, 20}, {4, 10}, {5, 40}}
+	customers := []testCustomer{{10, "alice"}, {20, "bob"}, {20, "bobby"}, {50, "eve"}, {30, "carol"}}
+	for _, newStream := range []func(data interface{}) Stream{NewSequentialStream, NewParallelStream} {
+		tests := []struct {
+			name string
+			s    Stream
+			want []interface{}
+		}{
+			{"test join", newStream(orders).Join(NewSequentialStream(customers), testOrderKeys, testOrderCustomer),
+				[]interface{}{"1:alice", "2:carol", "3:bob", "3:bobby", "4:alice"}},
+			{"test left join", newStream(orders).LeftJoin(NewSequentialStream(customers), testOrderKeys, testOrderCustomer),
+				[]interface{}{"1:alice", "2:carol", "3:bob", "3:bobby", "4:alice", "5:-"}},
+			{"test full outer join", newStream(orders).FullOuterJoin(NewParallelStream(customers), testOrderKeys, testOrderCustomer),
+				[]interface{}{"1:alice", "2:carol", "3:bob", "3:bobby", "4:alice", "5:-", "-:eve"}},
+			{"test semi join", newStream(orders).SemiJoin(NewSequentialStream(customers), testOrderKeys),
+				[]interface{}{orders[0], orders[1], orders[2], orders[3]}},
+			{"test anti join", newStream(orders).AntiJoin(NewSequentialStream(customers), testOrderKeys),
+				[]interface{}{orders[4]}},
+			{"test empty other", newStream(orders).Join(NewSequentialStream([]testCustomer{}), testOrderKeys, testOrderCustomer),
+				[]interface{}{}},
+			{"test empty stream", newStream([]testOrder{}).LeftJoin(NewSequentialStream(customers), testOrderKeys, testOrderCustomer),
+				[]interface{}{}},
+			{"test full outer join of empty stream", newStream([]testOrder{}).FullOuterJoin(NewSequentialStream(customers[:2]), testOrderKeys, testOrderCustomer),
+				[]interface{}{"-:alice", "-:bob"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var got []interface{}
+				assert.NoError(t, tt.s.Collect(&got))
+				assert.Equal(t, tt.want, got)
+			})
+		}
+	}
+}
+
+func TestStreamJoinCustomKeys(t *testing.T) {
+	// the slices are not comparable, so they are matched by the hashcode and equals of the keys
+	keys := JoinKeys{
+		Key: func(val interface{}) interface{} {
+			return strings.Fields(val.(string))
+		},
+		OtherKey: func(val interface{}) interface{} {
+			return strings.Split(val.(string), ",")
+		},
+		Hashcode: func(key interface{}) interface{} {
+			return len(key.([]string))
+		},
+		Equals: func(a, b interface{}) bool {
+			return strings.Join(a.([]string), ",") == strings.Join(b.([]string), ",")
+		},
+	}
+	words := make([]string, 1000)
+	for i := range words {
+		words[i] = fmt.Sprintf("%d %d", i%10, i%7)
+	}
+	for _, newStream := range []func(data interface{}) Stream{NewSequentialStream, NewParallelStream} {
+		var got []interface{}
+		err := newStream(words).Join(NewSequentialStream([]string{"1,1", "3,5", "1"}), keys, func(val, other interface{}) interface{} {
+			return other
+		}).Collect(&got)
+		assert.NoError(t, err)
+		assert.Len(t, got, 29)
+		assert.Equal(t, []interface{}{"1,1", "3,5", "1,1"}, got[:3])
+
+		count, err := newStream(words).AntiJoin(NewSequentialStream([]string{"1,1", "3,5", "1"}), keys).Count()
+		assert.NoError(t, err)
+		assert.Equal(t, 971, count)
+	}
+
+	_, err := NewSequentialStream([]string{"a"}).SemiJoin(NewSequentialStream([]string{"a"}), JoinKeys{
+		Key:      keys.Key,
+		OtherKey: keys.OtherKey,
+	}).Count()
+	var p *PanicError
+	if assert.True(t, errors.As(err, &p)) {
+		assert.Equal(t, "SemiJoin", p.Op)
+	}
+}
+
+func TestStreamJoinError(t *testing.T) {
+	boom := panicAt(2)
+	orders := []testOrder{{0, 10}, {1, 20}, {2, 30}, {3, 40}}
+	customers := []testCustomer{{10, "alice"}, {20, "bob"}}
+	for _, newStream := range []func(data interface{}) Stream{NewSequentialStream, NewParallelStream} {
+		s := newStream(orders).Join(NewSequentialStream(customers), testOrderKeys, func(val, other interface{}) interface{} {
+			boom(val.(testOrder).id)
+			return val
+		})
+		_, err := s.Count()
+		assert.NoError(t, err)
+		s = newStream(orders).LeftJoin(NewSequentialStream(customers), testOrderKeys, func(val, other interface{}) interface{} {
+			boom(val.(testOrder).id)
+			return val
+		})
+		_, err = s.Count()
+		assertPanicError(t, err, "LeftJoin", 2)
+		assertPanicError(t, s.Err(), "LeftJoin", 2)
+
+		_, err = newStream(orders).FullOuterJoin(NewSequentialStream(customers), JoinKeys{
+			Key: testOrderKeys.Key,
+			OtherKey: func(val interface{}) interface{} {
+				panic("boom")
+			},
+		}, testOrderCustomer).Count()
+		assertPanicError(t, err, "FullOuterJoin", -1)
+
+		_, err = newStream(orders).SemiJoin(testErrStream, testOrderKeys).Count()
+		assert.Error(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = newStream(orders).WithContext(ctx).AntiJoin(NewSequentialStream(customers), testOrderKeys).Count()
+		assert.Equal(t, context.Canceled, err)
+	}
+
+	assert.Error(t, testErrStream.Join(NewSequentialStream([]int{1}), testOrderKeys, testOrderCustomer).Err())
+	assert.Error(t, testErrStream.LeftJoin(NewSequentialStream([]int{1}), testOrderKeys, testOrderCustomer).Err())
+	assert.Error(t, testErrStream.FullOuterJoin(NewSequentialStream([]int{1}), testOrderKeys, testOrderCustomer).Err())
+	assert.Error(t, testErrStream.SemiJoin(NewSequentialStream([]int{1}), testOrderKeys).Err())
+	assert.Error(t, testErrStream.AntiJoin(NewSequentialStream([]int{1}), testOrderKeys).Err())
+}
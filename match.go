@@ -0,0 +1,290 @@
+package gostream
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+func (s *sequentialStream) AnyMatch(predicate func(val interface{}) (match bool)) (bool, error) {
+	found, err := s.find("AnyMatch", predicate)
+	return found != nil, err
+}
+
+func (s *sequentialStream) AllMatch(predicate func(val interface{}) (match bool)) (bool, error) {
+	found, err := s.find("AllMatch", func(val interface{}) bool {
+		return !predicate(val)
+	})
+	return found == nil && err == nil, err
+}
+
+func (s *sequentialStream) NoneMatch(predicate func(val interface{}) (match bool)) (bool, error) {
+	found, err := s.find("NoneMatch", predicate)
+	return found == nil && err == nil, err
+}
+
+func (s *sequentialStream) FindFirst() (interface{}, error) {
+	found, err := s.find("FindFirst", nil)
+	if found == nil {
+		return nil, err
+	}
+	return found.data, err
+}
+
+func (s *sequentialStream) FindAny() (interface{}, error) {
+	return s.FindFirst()
+}
+
+// find pulls the elements until an element matching predicate is found, the upstream stages are not evaluated any
+// further. The first element is matched if predicate is nil, and nil is returned if no element matches.
+func (s *sequentialStream) find(op string, predicate func(val interface{}) (match bool)) (*element, error) {
+	it := s.iterator()
+	index := 0
+	var found *element
+	err := guardAt(op, &index, func() {
+		for e, ok := it.next(); ok; e, ok = it.next() {
+			if predicate == nil || predicate(e.data) {
+				found = e
+				return
+			}
+			index++
+		}
+	})
+	if err == nil && found == nil {
+		err = it.err()
+	}
+	s.record(err)
+	if err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+func (p *parallelStream) AnyMatch(predicate func(val interface{}) (match bool)) (bool, error) {
+	found, err := p.find("AnyMatch", predicate)
+	return found != nil, err
+}
+
+func (p *parallelStream) AllMatch(predicate func(val interface{}) (match bool)) (bool, error) {
+	found, err := p.find("AllMatch", func(val interface{}) bool {
+		return !predicate(val)
+	})
+	return found == nil && err == nil, err
+}
+
+func (p *parallelStream) NoneMatch(predicate func(val interface{}) (match bool)) (bool, error) {
+	found, err := p.find("NoneMatch", predicate)
+	return found == nil && err == nil, err
+}
+
+// FindFirst of a parallel stream pulls the first element only, the upstream stages are evaluated no further than
+// needed.
+func (p *parallelStream) FindFirst() (interface{}, error) {
+	elements, err := p.head(1)
+	if err != nil || len(elements) == 0 {
+		return nil, err
+	}
+	return elements[0].data, nil
+}
+
+// FindAny of a parallel stream returns the element reached first by any of the goroutines, the elements are pulled in
+// batches, so only the first batch is evaluated.
+func (p *parallelStream) FindAny() (interface{}, error) {
+	found, err := p.find("FindAny", func(interface{}) bool {
+		return true
+	})
+	if found == nil {
+		return nil, err
+	}
+	return found.data, err
+}
+
+// find pulls the elements in batches, and tests the elements of each batch concurrently. The outstanding tests are
+// cancelled and no more element is pulled once an element matching predicate is found, which may be any of the
+// matched elements of the batch. nil is returned if no element matches.
+func (p *parallelStream) find(op string, predicate func(val interface{}) (match bool)) (*element, error) {
+	it := p.iterator()
+	offset := 0
+	for size := firstBatchSize(p.parallelism); ; size = nextBatchSize(size) {
+		batch := pullBatch(it, size)
+		if len(batch) == 0 {
+			break
+		}
+		index, err := parallelFind(p.ctx, op, p.parallelism, offset, len(batch), func(i int) bool {
+			return predicate(batch[i].data)
+		})
+		if err != nil {
+			p.record(err)
+			return nil, err
+		}
+		if index >= 0 {
+			return batch[index], nil
+		}
+		offset += len(batch)
+	}
+	err := it.err()
+	p.record(err)
+	return nil, err
+}
+
+func (e *errStream) AnyMatch(func(val interface{}) (match bool)) (bool, error) {
+	return false, e.err
+}
+
+func (e *errStream) AllMatch(func(val interface{}) (match bool)) (bool, error) {
+	return false, e.err
+}
+
+func (e *errStream) NoneMatch(func(val interface{}) (match bool)) (bool, error) {
+	return false, e.err
+}
+
+func (e *errStream) FindFirst() (interface{}, error) {
+	return nil, e.err
+}
+
+func (e *errStream) FindAny() (interface{}, error) {
+	return nil, e.err
+}
+
+func (s *sequentialNumberStream[N]) AnyMatch(predicate func(val N) (match bool)) (bool, error) {
+	index, err := sequentialFind(s.ctx, "AnyMatch", len(s.elements), func(i int) bool {
+		return predicate(s.elements[i])
+	})
+	return index >= 0, err
+}
+
+func (s *sequentialNumberStream[N]) AllMatch(predicate func(val N) (match bool)) (bool, error) {
+	index, err := sequentialFind(s.ctx, "AllMatch", len(s.elements), func(i int) bool {
+		return !predicate(s.elements[i])
+	})
+	return index < 0 && err == nil, err
+}
+
+func (s *sequentialNumberStream[N]) NoneMatch(predicate func(val N) (match bool)) (bool, error) {
+	index, err := sequentialFind(s.ctx, "NoneMatch", len(s.elements), func(i int) bool {
+		return predicate(s.elements[i])
+	})
+	return index < 0 && err == nil, err
+}
+
+func (s *sequentialNumberStream[N]) FindFirst() (*N, error) {
+	if err := ctxErr(s.ctx); err != nil {
+		return nil, err
+	}
+	if len(s.elements) == 0 {
+		return nil, nil
+	}
+	result := s.elements[0]
+	return &result, nil
+}
+
+func (s *sequentialNumberStream[N]) FindAny() (*N, error) {
+	return s.FindFirst()
+}
+
+func (p *parallelNumberStream[N]) AnyMatch(predicate func(val N) (match bool)) (bool, error) {
+	index, err := parallelFind(p.ctx, "AnyMatch", p.parallelism, 0, len(p.elements), func(i int) bool {
+		return predicate(p.elements[i])
+	})
+	return index >= 0, err
+}
+
+func (p *parallelNumberStream[N]) AllMatch(predicate func(val N) (match bool)) (bool, error) {
+	index, err := parallelFind(p.ctx, "AllMatch", p.parallelism, 0, len(p.elements), func(i int) bool {
+		return !predicate(p.elements[i])
+	})
+	return index < 0 && err == nil, err
+}
+
+func (p *parallelNumberStream[N]) NoneMatch(predicate func(val N) (match bool)) (bool, error) {
+	index, err := parallelFind(p.ctx, "NoneMatch", p.parallelism, 0, len(p.elements), func(i int) bool {
+		return predicate(p.elements[i])
+	})
+	return index < 0 && err == nil, err
+}
+
+func (p *parallelNumberStream[N]) FindFirst() (*N, error) {
+	if err := ctxErr(p.ctx); err != nil {
+		return nil, err
+	}
+	if len(p.elements) == 0 {
+		return nil, nil
+	}
+	result := p.elements[0]
+	return &result, nil
+}
+
+func (p *parallelNumberStream[N]) FindAny() (*N, error) {
+	return p.FindFirst()
+}
+
+func (e *errNumberStream[N]) AnyMatch(func(val N) (match bool)) (bool, error) {
+	return false, e.err
+}
+
+func (e *errNumberStream[N]) AllMatch(func(val N) (match bool)) (bool, error) {
+	return false, e.err
+}
+
+func (e *errNumberStream[N]) NoneMatch(func(val N) (match bool)) (bool, error) {
+	return false, e.err
+}
+
+func (e *errNumberStream[N]) FindFirst() (*N, error) {
+	return nil, e.err
+}
+
+func (e *errNumberStream[N]) FindAny() (*N, error) {
+	return nil, e.err
+}
+
+// sequentialFind calls match with each index in [0, n) in order until it returns true, the index is returned, or -1
+// if no index matched. It stops once ctx is done or match panics, and the error of ctx or the *PanicError of op is
+// returned.
+func sequentialFind(ctx context.Context, op string, n int, match func(i int) bool) (index int, err error) {
+	index = -1
+	i := 0
+	err = guardAt(op, &i, func() {
+		for ; i < n && ctxErr(ctx) == nil; i++ {
+			if match(i) {
+				index = i
+				return
+			}
+		}
+	})
+	if err == nil && index < 0 {
+		err = ctxErr(ctx)
+	}
+	if err != nil {
+		return -1, err
+	}
+	return index, nil
+}
+
+// parallelFind calls match with each index in [0, n) concurrently on the default executor, no more call is scheduled
+// once any of them returns true, whose index is returned. -1 is returned if no index matched.
+// It stops once ctx is done or match panics, and the error of ctx or the *PanicError of op is returned, offset is
+// added to the index reported by the *PanicError.
+func parallelFind(ctx context.Context, op string, parallelism, offset, n int, match func(i int) bool) (int, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	findCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	found := int64(-1)
+	err := parallelEachAt(findCtx, op, parallelism, offset, n, func(i int) {
+		if match(i) && atomic.CompareAndSwapInt64(&found, -1, int64(i)) {
+			cancel()
+		}
+	})
+	if _, ok := err.(*PanicError); ok {
+		return -1, err
+	}
+	if found >= 0 {
+		return int(found), nil
+	}
+	if err != nil {
+		return -1, ctxErr(ctx)
+	}
+	return -1, nil
+}
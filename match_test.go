@@ -0,0 +1,225 @@
+package gostream
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+)
+
+func TestStreamMatch(t *testing.T) {
+	isEven := func(val interface{}) (match bool) {
+		return val.(int)%2 == 0
+	}
+	tests := []struct {
+		name                          string
+		ints                          []int
+		anyMatch, allMatch, noneMatch bool
+	}{
+		{"test empty", nil, false, true, true},
+		{"test all match", []int{2, 4}, true, true, false},
+		{"test some match", []int{1, 2, 3}, true, false, false},
+		{"test none match", []int{1, 3}, false, false, true},
+	}
+	for _, newStream := range []func([]*element) Stream{newSequentialStreamForTest, newParallelStreamForTest} {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				s := newStream(intSliceToElements(tt.ints))
+				match, err := s.AnyMatch(isEven)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.anyMatch, match)
+				match, err = s.AllMatch(isEven)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.allMatch, match)
+				match, err = s.NoneMatch(isEven)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.noneMatch, match)
+			})
+		}
+	}
+
+	for _, s := range []Stream{testErrStream, testErrStream.Parallel()} {
+		_, err := s.AnyMatch(isEven)
+		assert.Error(t, err)
+		_, err = s.AllMatch(isEven)
+		assert.Error(t, err)
+		_, err = s.NoneMatch(isEven)
+		assert.Error(t, err)
+		_, err = s.FindFirst()
+		assert.Error(t, err)
+		_, err = s.FindAny()
+		assert.Error(t, err)
+	}
+}
+
+func TestStreamMatchShortCircuit(t *testing.T) {
+	ints := make([]int, 10000)
+	for i := range ints {
+		ints[i] = i
+	}
+	var mapped, tested int32
+	match, err := NewSequentialStream(ints).Map(func(src interface{}) (dest interface{}) {
+		atomic.AddInt32(&mapped, 1)
+		return src
+	}).AnyMatch(func(val interface{}) (match bool) {
+		atomic.AddInt32(&tested, 1)
+		return val.(int) == 2
+	})
+	assert.NoError(t, err)
+	assert.True(t, match)
+	assert.Equal(t, int32(3), mapped)
+	assert.Equal(t, int32(3), tested)
+
+	tested = 0
+	match, err = NewParallelStream(ints).WithParallelism(2).AllMatch(func(val interface{}) (match bool) {
+		atomic.AddInt32(&tested, 1)
+		return val.(int) != 0
+	})
+	assert.NoError(t, err)
+	assert.False(t, match)
+	assert.True(t, atomic.LoadInt32(&tested) < int32(len(ints)))
+
+	// the upstream elements are pulled in batches, so the elements after the batch of the match are never produced
+	var produced int32
+	match, err = Iterate(0, func(val interface{}) bool {
+		return val.(int) < len(ints)
+	}, func(prev interface{}) interface{} {
+		atomic.AddInt32(&produced, 1)
+		return prev.(int) + 1
+	}).Parallel().AnyMatch(func(val interface{}) (match bool) {
+		return val.(int) == 5
+	})
+	assert.NoError(t, err)
+	assert.True(t, match)
+	assert.True(t, atomic.LoadInt32(&produced) < int32(len(ints)/2))
+
+	match, err = Generate(func() interface{} {
+		return 1
+	}).Parallel().NoneMatch(func(val interface{}) (match bool) {
+		return val.(int) == 1
+	})
+	assert.NoError(t, err)
+	assert.False(t, match)
+
+	boom := panicAt(5000)
+	_, err = NewParallelStream(ints).AllMatch(func(val interface{}) (match bool) {
+		boom(val.(int))
+		return true
+	})
+	assertPanicError(t, err, "AllMatch", 5000)
+}
+
+func TestStreamFind(t *testing.T) {
+	for _, newStream := range []func([]*element) Stream{newSequentialStreamForTest, newParallelStreamForTest} {
+		s := newStream(intSliceToElements([]int{1, 2, 3})).Filter(func(val interface{}) (match bool) {
+			return val.(int) > 1
+		})
+		first, err := s.FindFirst()
+		assert.NoError(t, err)
+		assert.Equal(t, 2, first)
+		found, err := s.FindAny()
+		assert.NoError(t, err)
+		assert.Contains(t, []interface{}{2, 3}, found)
+
+		first, err = newStream(nil).FindFirst()
+		assert.NoError(t, err)
+		assert.Nil(t, first)
+	}
+
+	var mapped int32
+	first, err := NewSequentialStream([]int{1, 2, 3}).Map(func(src interface{}) (dest interface{}) {
+		atomic.AddInt32(&mapped, 1)
+		return src
+	}).FindFirst()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, first)
+	assert.Equal(t, int32(1), mapped)
+
+	first, err = Iterate(1, nil, func(prev interface{}) interface{} {
+		return prev.(int) + 1
+	}).Parallel().FindFirst()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, first)
+	found, err := Generate(func() interface{} {
+		return 7
+	}).Parallel().FindAny()
+	assert.NoError(t, err)
+	assert.Equal(t, 7, found)
+
+	_, err = NewSequentialStream([]int{1}).AnyMatch(func(val interface{}) (match bool) {
+		panic("boom")
+	})
+	assertPanicError(t, err, "AnyMatch", 0)
+	_, err = NewParallelStream([]int{1}).NoneMatch(func(val interface{}) (match bool) {
+		panic("boom")
+	})
+	assertPanicError(t, err, "NoneMatch", 0)
+}
+
+func TestIntStreamMatch(t *testing.T) {
+	isEven := func(val int) (match bool) {
+		return val%2 == 0
+	}
+	for _, newStream := range []func([]int) IntStream{NewSequentialIntStream, NewParallelIntStream} {
+		s := newStream([]int{1, 2, 3})
+		match, err := s.AnyMatch(isEven)
+		assert.NoError(t, err)
+		assert.True(t, match)
+		match, err = s.AllMatch(isEven)
+		assert.NoError(t, err)
+		assert.False(t, match)
+		match, err = s.NoneMatch(isEven)
+		assert.NoError(t, err)
+		assert.False(t, match)
+		match, err = newStream(nil).AllMatch(isEven)
+		assert.NoError(t, err)
+		assert.True(t, match)
+
+		first, err := s.FindFirst()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, *first)
+		found, err := s.FindAny()
+		assert.NoError(t, err)
+		assert.Contains(t, []int{1, 2, 3}, *found)
+		first, err = newStream(nil).FindFirst()
+		assert.NoError(t, err)
+		assert.Nil(t, first)
+
+		_, err = s.AllMatch(func(val int) (match bool) {
+			panic("boom")
+		})
+		assertPanicError(t, err, "AllMatch", 0)
+	}
+	_, err := testErrIntStream.AnyMatch(isEven)
+	assert.Error(t, err)
+	_, err = testErrIntStream.FindFirst()
+	assert.Error(t, err)
+}
+
+func TestFloat64StreamMatch(t *testing.T) {
+	isPositive := func(val float64) (match bool) {
+		return val > 0
+	}
+	for _, newStream := range []func([]float64) Float64Stream{NewSequentialFloat64Stream, NewParallelFloat64Stream} {
+		s := newStream([]float64{-1, 1})
+		match, err := s.AnyMatch(isPositive)
+		assert.NoError(t, err)
+		assert.True(t, match)
+		match, err = s.AllMatch(isPositive)
+		assert.NoError(t, err)
+		assert.False(t, match)
+		match, err = s.NoneMatch(isPositive)
+		assert.NoError(t, err)
+		assert.False(t, match)
+
+		first, err := s.FindFirst()
+		assert.NoError(t, err)
+		assert.Equal(t, -1.0, *first)
+		found, err := newStream(nil).FindAny()
+		assert.NoError(t, err)
+		assert.Nil(t, found)
+	}
+	_, err := testSerialErrFloat64Stream.NoneMatch(isPositive)
+	assert.Error(t, err)
+	_, err = testSerialErrFloat64Stream.FindAny()
+	assert.Error(t, err)
+}
@@ -0,0 +1,1165 @@
+package gostream
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+//go:generate go run ./cmd/streamgen -type int -tests
+//go:generate go run ./cmd/streamgen -type int64 -tests
+//go:generate go run ./cmd/streamgen -type uint64 -tests
+//go:generate go run ./cmd/streamgen -type float64 -output f64_stream.go -tests
+
+// Number is a constraint that permits any integer or floating-point type, it's the element type of NumberStream.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// NumberStream is a sequence of numeric elements supporting sequential and parallel aggregate operations.
+// IntStream, Int64Stream, Uint64Stream and Float64Stream are its instantiations.
+type NumberStream[N Number] interface {
+	BaseStream
+	// AllMatch returns whether all the elements of this stream match predicate, true is returned if the stream is
+	// empty. The elements are no longer tested once an element doesn't match.
+	AllMatch(predicate func(val N) (match bool)) (bool, error)
+	// AnyMatch returns whether any element of this stream matches predicate, false is returned if the stream is
+	// empty. The elements are no longer tested once an element matches.
+	AnyMatch(predicate func(val N) (match bool)) (bool, error)
+	// Average returns a *float64 describing the arithmetic mean of elements of this stream, or a nil pointer
+	// if this stream is empty.
+	Average() (*float64, error)
+	// Chan returns a channel receiving the elements of this stream in encounter order, the channel is closed after
+	// all the elements were sent.
+	Chan() <-chan N
+	// Chunk returns a Stream consisting of the slices of n consecutive elements of this stream in encounter order, the
+	// last slice has fewer elements if the length of this stream is not a multiple of n. The slices are of type []N.
+	// An error will occur if n is not positive.
+	Chunk(n int) Stream
+	// Collect returns a slice consisting of the elements of this stream.
+	Collect() ([]N, error)
+	// Distinct returns a stream consisting of the distinct elements of this stream.
+	Distinct() NumberStream[N]
+	// DropWhile returns a stream consisting of the remaining elements of this stream after dropping the longest prefix
+	// of elements matching predicate. The elements after the first mismatch are no longer tested.
+	DropWhile(predicate func(val N) (match bool)) NumberStream[N]
+	// Filter returns a stream consisting of the elements of this stream that match the given predicate.
+	Filter(predicate func(val N) (match bool)) NumberStream[N]
+	// FilterE is the Filter whose predicate may fail, the errors returned are handled according to the error
+	// mode of this stream.
+	FilterE(predicate func(val N) (match bool, err error)) NumberStream[N]
+	// FlatMap returns a stream consisting of the results of replacing each element of this stream with the contents
+	// of a mapped stream produced by applying the provided mapper to each element.
+	FlatMap(mapper func(val N) NumberStream[N]) NumberStream[N]
+	// FlatMapE is the FlatMap whose mapper may fail, the errors returned are handled according to the error
+	// mode of this stream.
+	FlatMapE(mapper func(val N) (NumberStream[N], error)) NumberStream[N]
+	// FindAny returns a pointer describing some element of this stream, or a nil pointer if the stream is empty.
+	// A parallel stream may return any of its elements.
+	FindAny() (*N, error)
+	// FindFirst returns a pointer describing the first element of this stream, or a nil pointer if the stream is
+	// empty.
+	FindFirst() (*N, error)
+	// ForEach performs action for each element of this stream. A parallel stream performs the actions concurrently in
+	// no particular order, so action should be safe for concurrent use.
+	ForEach(action func(val N)) error
+	// ForEachOrdered performs action for each element of this stream one at a time in encounter order.
+	ForEachOrdered(action func(val N)) error
+	// Limit returns a stream consisting of the elements of this stream, truncated to be no longer than maxSize in
+	// length.
+	// An error will occur when maxSize is negative.
+	Limit(maxSize int) NumberStream[N]
+	// Map returns a stream consisting of results of applying the given mapper to the elements of this stream.
+	Map(mapper func(src N) (dest N)) NumberStream[N]
+	// MapE is the Map whose mapper may fail, the errors returned are handled according to the error mode of this
+	// stream.
+	MapE(mapper func(src N) (dest N, err error)) NumberStream[N]
+	// MapToFloat64 returns a Float64Stream consisting of the results of applying the given mapper to the elements of
+	// this stream.
+	MapToFloat64(mapper func(src N) (dest float64)) Float64Stream
+	// MapToInt returns an IntStream consisting of the results of applying the given mapper to the elements of
+	// this stream.
+	MapToInt(mapper func(src N) (dest int)) IntStream
+	// MapToInt64 returns an Int64Stream consisting of the results of applying the given mapper to the elements of
+	// this stream.
+	MapToInt64(mapper func(src N) (dest int64)) Int64Stream
+	// MapToObj returns an object-valued Stream consisting of the results of applying the given mapper to the elements
+	// of this stream.
+	MapToObj(mapper func(src N) (dest interface{})) Stream
+	// MapToString returns a StringStream consisting of the results of applying the given mapper to the elements of
+	// this stream.
+	MapToString(mapper func(src N) (dest string)) StringStream
+	// Max returns a pointer describing the maximum element of this stream, or a nil pointer if the stream is empty.
+	Max() (*N, error)
+	// Median returns a *float64 describing the median of elements of this stream, which is the 50th percentile, or a
+	// nil pointer if this stream is empty.
+	Median() (*float64, error)
+	// Min returns a pointer describing the minimum element of this stream, or a nil pointer if the stream is empty.
+	Min() (*N, error)
+	// MovingAverage returns a Float64Stream consisting of the arithmetic means of the sliding windows of size
+	// consecutive elements of this stream, which is empty if this stream has fewer than size elements.
+	// An error will occur if size is not positive.
+	MovingAverage(size int) Float64Stream
+	// NoneMatch returns whether no element of this stream matches predicate, true is returned if the stream is
+	// empty. The elements are no longer tested once an element matches.
+	NoneMatch(predicate func(val N) (match bool)) (bool, error)
+	// Parallel returns an equivalent stream that is parallel. May return itself, because the stream was already
+	// parallel.
+	Parallel() NumberStream[N]
+	// Peek returns a stream consisting of the elements of this stream, additionally performing action on each element.
+	// It's mainly to support debugging, a parallel stream performs the actions concurrently.
+	Peek(action func(val N)) NumberStream[N]
+	// Percentile returns a *float64 describing the p-th percentile of elements of this stream, interpolated linearly
+	// between the closest ranks, or a nil pointer if this stream is empty. An error will occur if p is not in
+	// [0, 100].
+	Percentile(p float64) (*float64, error)
+	// Reduce performs a reduction on the elements of this stream, using an associative accumulation function, and
+	// returns a pointer describing the reduced value, if any, or a nil pointer if the stream is empty.
+	Reduce(op func(a, b N) (c N)) (*N, error)
+	// ReduceWithIdentity performs a reduction on the elements of this stream, using identity and an associative
+	// accumulation function, and returns the reduced value, which is identity if the stream is empty. identity should
+	// be the identity of op, since a parallel stream starts the reduction of each partition from it.
+	ReduceWithIdentity(identity N, op func(a, b N) (c N)) (N, error)
+	// Sequential returns an equivalent stream that is sequential. May return itself, because the stream was already
+	// sequential.
+	Sequential() NumberStream[N]
+	// Skip returns a stream consisting of the remaining elements of this stream after discarding the first n elements
+	// of the stream. If this stream contains fewer than n elements then an empty stream will be returned.
+	// An error will occur if n is negative.
+	Skip(n int) NumberStream[N]
+	// Sorted returns a stream consisting of the elements of this stream in sorted order, NaN is ordered before any
+	// other value.
+	Sorted() NumberStream[N]
+	// SortedDescending returns a stream consisting of the elements of this stream in descending order, NaN is ordered
+	// after any other value.
+	SortedDescending() NumberStream[N]
+	// SortedWith returns a stream consisting of the elements of this stream, sorted according to less. The sort is
+	// stable, so the elements less considers equal keep their encounter order.
+	SortedWith(less func(a, b N) bool) NumberStream[N]
+	// Sum returns the sum of elements in this stream. The floating-point elements are summed with Kahan-Neumaier
+	// compensated summation, and the sum of integers wraps around on overflow like the + operator.
+	Sum() (N, error)
+	// SumChecked is the Sum returning an error wrapping ErrOverflow if the sum is out of the range of N. The sum of
+	// integers is computed exactly, so an overflow in the middle of the summation is not reported if the final sum is
+	// in range.
+	SumChecked() (N, error)
+	// SummaryStatistics returns the count, sum, min, max, average and variance of elements of this stream, computed
+	// in a single pass. A parallel stream summarizes its chunks concurrently and combines the partial statistics.
+	SummaryStatistics() (*NumberSummaryStatistics[N], error)
+	// TakeWhile returns a stream consisting of the longest prefix of elements of this stream matching predicate, the
+	// elements are no longer tested once an element doesn't match. A parallel stream tests the elements concurrently
+	// in batches, and keeps the encounter order.
+	TakeWhile(predicate func(val N) (match bool)) NumberStream[N]
+	// ToChan sends the elements of this stream to ch in encounter order, and closes ch after all the elements were
+	// sent.
+	ToChan(ch chan<- N) error
+	// Window returns a Stream consisting of the sliding windows of size consecutive elements of this stream, a window
+	// starts every step elements, so the windows overlap if step is less than size. Only the full windows are
+	// produced, and they are of type []N. An error will occur if size or step is not positive.
+	Window(size, step int) Stream
+	// WithContext returns an equivalent stream whose operations are cancelled once ctx is done, the error of ctx is
+	// reported by Err and the terminal operations.
+	WithContext(ctx context.Context) NumberStream[N]
+	// WithErrorMode returns an equivalent stream handling the errors returned by the functions supplied to MapE,
+	// FilterE and FlatMapE according to mode. An error will occur if mode is unknown.
+	WithErrorMode(mode ErrorMode) NumberStream[N]
+	// WithParallelism returns an equivalent stream whose parallel operations run on at most n goroutines, an error
+	// will occur if n is not positive. It has no effect on the sequential operations.
+	WithParallelism(n int) NumberStream[N]
+}
+
+type sequentialNumberStream[N Number] struct {
+	elements []N
+	config
+}
+
+type parallelNumberStream[N Number] struct {
+	elements []N
+	config
+}
+
+type errNumberStream[N Number] struct {
+	err      error
+	parallel bool
+}
+
+// NewSequentialNumberStream returns a sequential ordered stream whose elements are the specified numbers.
+func NewSequentialNumberStream[N Number](numbers []N) NumberStream[N] {
+	return &sequentialNumberStream[N]{elements: numbers}
+}
+
+// NewParallelNumberStream returns a parallel stream whose elements are the specified numbers.
+func NewParallelNumberStream[N Number](numbers []N) NumberStream[N] {
+	return &parallelNumberStream[N]{elements: numbers}
+}
+
+// ConcatNumberStream creates a concatenated sequential stream whose elements are all the elements of the first stream
+// followed by all the elements of the second stream.
+func ConcatNumberStream[N Number](a, b NumberStream[N]) NumberStream[N] {
+	aElements, err := a.Collect()
+	if err != nil {
+		return &errNumberStream[N]{err: err}
+	}
+	bElements, err := b.Collect()
+	if err != nil {
+		return &errNumberStream[N]{err: err}
+	}
+	return &sequentialNumberStream[N]{elements: append(aElements, bElements...)}
+}
+
+// ZipNumber creates a sequential stream whose elements are the results of applying combiner to the elements of a and b
+// at the same position, the stream is as long as the shorter one of a and b.
+func ZipNumber[N Number](a, b NumberStream[N], combiner func(first, second N) N) NumberStream[N] {
+	zipped := Zip(untypedNumberStream(a), untypedNumberStream(b), func(first, second interface{}) interface{} {
+		return combiner(first.(N), second.(N))
+	})
+	return newLazyNumberStream[N](zipped)
+}
+
+func (s *sequentialNumberStream[N]) with(elements []N) *sequentialNumberStream[N] {
+	return &sequentialNumberStream[N]{elements: elements, config: s.config}
+}
+
+func (s *sequentialNumberStream[N]) MapToObj(mapper func(src N) (dest interface{})) Stream {
+	if len(s.elements) <= 0 {
+		return newSequentialStream(s.config, nil)
+	}
+	dest := make([]*element, len(s.elements))
+	err := sequentialEach(s.ctx, "MapToObj", len(s.elements), func(i int) {
+		dest[i] = newElement(mapper(s.elements[i]))
+	})
+	if err != nil {
+		return &errStream{err: err}
+	}
+	return newSequentialStream(s.config, sliceStage(dest))
+}
+
+func (s *sequentialNumberStream[N]) Err() error {
+	return ctxErr(s.ctx)
+}
+
+func (s *sequentialNumberStream[N]) IsParallel() bool {
+	return false
+}
+
+func (s *sequentialNumberStream[N]) Average() (*float64, error) {
+	if err := ctxErr(s.ctx); err != nil {
+		return nil, err
+	}
+	if len(s.elements) == 0 {
+		return nil, nil
+	}
+	result := averageNumbers(s.elements)
+	return &result, nil
+}
+
+func (s *sequentialNumberStream[N]) Collect() ([]N, error) {
+	if err := ctxErr(s.ctx); err != nil {
+		return nil, err
+	}
+	result := make([]N, len(s.elements))
+	copy(result, s.elements)
+	return result, nil
+}
+
+func (s *sequentialNumberStream[N]) Distinct() NumberStream[N] {
+	if len(s.elements) <= 1 {
+		return s
+	}
+	return s.with(distinctNumbers(s.elements))
+}
+
+func (s *sequentialNumberStream[N]) DropWhile(predicate func(val N) (match bool)) NumberStream[N] {
+	n, err := matchingPrefix(s.ctx, "DropWhile", 1, 0, s.elements, predicate)
+	if err != nil {
+		return &errNumberStream[N]{err: err}
+	}
+	if n == 0 {
+		return s
+	}
+	return s.with(s.elements[n:])
+}
+
+func (s *sequentialNumberStream[N]) Filter(predicate func(val N) (keep bool)) NumberStream[N] {
+	if len(s.elements) == 0 {
+		return s
+	}
+	result := make([]N, 0)
+	err := sequentialEach(s.ctx, "Filter", len(s.elements), func(i int) {
+		if predicate(s.elements[i]) {
+			result = append(result, s.elements[i])
+		}
+	})
+	if err != nil {
+		return &errNumberStream[N]{err: err}
+	}
+	return s.with(result)
+}
+
+func (s *sequentialNumberStream[N]) FlatMap(mapper func(val N) NumberStream[N]) NumberStream[N] {
+	if len(s.elements) == 0 {
+		return s
+	}
+	streams := make([]NumberStream[N], len(s.elements))
+	err := sequentialEach(s.ctx, "FlatMap", len(s.elements), func(i int) {
+		streams[i] = mapper(s.elements[i])
+	})
+	if err != nil {
+		return &errNumberStream[N]{err: err}
+	}
+	newElements, err := concatNumbers(streams)
+	if err != nil {
+		return &errNumberStream[N]{err: err}
+	}
+	return s.with(newElements)
+}
+
+func (s *sequentialNumberStream[N]) Limit(maxSize int) NumberStream[N] {
+	if maxSize < 0 {
+		return &errNumberStream[N]{err: fmt.Errorf("limit error, maxSize less than 0: %d", maxSize)}
+	}
+	if maxSize >= len(s.elements) {
+		return s
+	}
+	return s.with(s.elements[:maxSize])
+}
+
+func (s *sequentialNumberStream[N]) Map(mapper func(src N) (dest N)) NumberStream[N] {
+	if len(s.elements) == 0 {
+		return s
+	}
+	newElements := make([]N, len(s.elements))
+	err := sequentialEach(s.ctx, "Map", len(s.elements), func(i int) {
+		newElements[i] = mapper(s.elements[i])
+	})
+	if err != nil {
+		return &errNumberStream[N]{err: err}
+	}
+	return s.with(newElements)
+}
+
+func (s *sequentialNumberStream[N]) MapToFloat64(mapper func(src N) (dest float64)) Float64Stream {
+	return sequentialMapToNumber(s, "MapToFloat64", mapper)
+}
+
+func (s *sequentialNumberStream[N]) MapToInt(mapper func(src N) (dest int)) IntStream {
+	return sequentialMapToNumber(s, "MapToInt", mapper)
+}
+
+func (s *sequentialNumberStream[N]) MapToInt64(mapper func(src N) (dest int64)) Int64Stream {
+	return sequentialMapToNumber(s, "MapToInt64", mapper)
+}
+
+func (s *sequentialNumberStream[N]) MapToString(mapper func(src N) (dest string)) StringStream {
+	return newStringStream(s.MapToObj(func(src N) (dest interface{}) {
+		return mapper(src)
+	}))
+}
+
+func (s *sequentialNumberStream[N]) Max() (*N, error) {
+	if err := ctxErr(s.ctx); err != nil {
+		return nil, err
+	}
+	if len(s.elements) == 0 {
+		return nil, nil
+	}
+	result := s.elements[0]
+	for i := 1; i < len(s.elements); i++ {
+		if e := s.elements[i]; e > result {
+			result = e
+		}
+	}
+	return &result, nil
+}
+
+func (s *sequentialNumberStream[N]) Min() (*N, error) {
+	if err := ctxErr(s.ctx); err != nil {
+		return nil, err
+	}
+	if len(s.elements) == 0 {
+		return nil, nil
+	}
+	result := s.elements[0]
+	for i := 1; i < len(s.elements); i++ {
+		if e := s.elements[i]; e < result {
+			result = e
+		}
+	}
+	return &result, nil
+}
+
+func (s *sequentialNumberStream[N]) Parallel() NumberStream[N] {
+	return &parallelNumberStream[N]{elements: s.elements, config: s.config}
+}
+
+func (s *sequentialNumberStream[N]) Reduce(op func(a, b N) (c N)) (*N, error) {
+	if err := ctxErr(s.ctx); err != nil {
+		return nil, err
+	}
+	if len(s.elements) == 0 {
+		return nil, nil
+	}
+	result := s.elements[0]
+	err := sequentialEach(s.ctx, "Reduce", len(s.elements), func(i int) {
+		if i > 0 {
+			result = op(result, s.elements[i])
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (s *sequentialNumberStream[N]) ReduceWithIdentity(identity N, op func(a, b N) (c N)) (N, error) {
+	result := identity
+	err := sequentialEach(s.ctx, "ReduceWithIdentity", len(s.elements), func(i int) {
+		result = op(result, s.elements[i])
+	})
+	if err != nil {
+		var zero N
+		return zero, err
+	}
+	return result, nil
+}
+
+func (s *sequentialNumberStream[N]) Sequential() NumberStream[N] {
+	return s
+}
+
+func (s *sequentialNumberStream[N]) Skip(n int) NumberStream[N] {
+	if n < 0 {
+		return &errNumberStream[N]{err: fmt.Errorf("skip error, skipN less than 0: %d", n)}
+	}
+	if n == 0 {
+		return s
+	}
+	if n >= len(s.elements) {
+		return s.with(nil)
+	}
+	return s.with(s.elements[n:])
+}
+
+func (s *sequentialNumberStream[N]) Sorted() NumberStream[N] {
+	if numbersAreSorted(s.elements) {
+		return s
+	}
+	return s.with(sortNumbers(s.elements))
+}
+
+func (s *sequentialNumberStream[N]) SortedDescending() NumberStream[N] {
+	return s.SortedWith(greaterNumber[N])
+}
+
+func (s *sequentialNumberStream[N]) SortedWith(less func(a, b N) bool) NumberStream[N] {
+	sorted, err := sortNumbersWith(s.elements, less)
+	if err != nil {
+		return &errNumberStream[N]{err: err}
+	}
+	return s.with(sorted)
+}
+
+func (s *sequentialNumberStream[N]) TakeWhile(predicate func(val N) (match bool)) NumberStream[N] {
+	n, err := matchingPrefix(s.ctx, "TakeWhile", 1, 0, s.elements, predicate)
+	if err != nil {
+		return &errNumberStream[N]{err: err}
+	}
+	if n == len(s.elements) {
+		return s
+	}
+	return s.with(s.elements[:n])
+}
+
+func (s *sequentialNumberStream[N]) Sum() (N, error) {
+	if err := ctxErr(s.ctx); err != nil {
+		return 0, err
+	}
+	return sumNumbers(s.elements), nil
+}
+
+func (s *sequentialNumberStream[N]) SumChecked() (N, error) {
+	if err := ctxErr(s.ctx); err != nil {
+		return 0, err
+	}
+	return summate(s.elements).checked()
+}
+
+func (s *sequentialNumberStream[N]) WithContext(ctx context.Context) NumberStream[N] {
+	return &sequentialNumberStream[N]{elements: s.elements, config: s.withContext(ctx)}
+}
+
+func (s *sequentialNumberStream[N]) WithParallelism(n int) NumberStream[N] {
+	if n <= 0 {
+		return &errNumberStream[N]{err: fmt.Errorf("parallelism error, n is not positive: %v", n)}
+	}
+	return &sequentialNumberStream[N]{elements: s.elements, config: s.withParallelism(n)}
+}
+
+func (s *sequentialNumberStream[N]) FilterE(predicate func(val N) (match bool, err error)) NumberStream[N] {
+	if len(s.elements) == 0 {
+		return s
+	}
+	matches := make([]bool, len(s.elements))
+	err := s.eachE("FilterE", false, len(s.elements), func(i int) (err error) {
+		matches[i], err = predicate(s.elements[i])
+		return err
+	})
+	if err != nil {
+		return &errNumberStream[N]{err: err}
+	}
+	return s.with(filterNumbers(s.elements, matches))
+}
+
+func (s *sequentialNumberStream[N]) FlatMapE(mapper func(val N) (NumberStream[N], error)) NumberStream[N] {
+	if len(s.elements) == 0 {
+		return s
+	}
+	streams := make([]NumberStream[N], len(s.elements))
+	err := s.eachE("FlatMapE", false, len(s.elements), func(i int) (err error) {
+		streams[i], err = mapper(s.elements[i])
+		return err
+	})
+	if err != nil {
+		return &errNumberStream[N]{err: err}
+	}
+	newElements, err := concatNumbers(streams)
+	if err != nil {
+		return &errNumberStream[N]{err: err}
+	}
+	return s.with(newElements)
+}
+
+func (s *sequentialNumberStream[N]) MapE(mapper func(src N) (dest N, err error)) NumberStream[N] {
+	if len(s.elements) == 0 {
+		return s
+	}
+	newElements := make([]N, len(s.elements))
+	err := s.eachE("MapE", false, len(s.elements), func(i int) (err error) {
+		newElements[i], err = mapper(s.elements[i])
+		return err
+	})
+	if err != nil {
+		return &errNumberStream[N]{err: err}
+	}
+	return s.with(newElements)
+}
+
+func (s *sequentialNumberStream[N]) WithErrorMode(mode ErrorMode) NumberStream[N] {
+	if err := checkErrorMode(mode); err != nil {
+		return &errNumberStream[N]{err: err}
+	}
+	return &sequentialNumberStream[N]{elements: s.elements, config: s.withErrorMode(mode)}
+}
+
+func (p *parallelNumberStream[N]) with(elements []N) *parallelNumberStream[N] {
+	return &parallelNumberStream[N]{elements: elements, config: p.config}
+}
+
+func (p *parallelNumberStream[N]) IsParallel() bool {
+	return true
+}
+
+func (p *parallelNumberStream[N]) Average() (*float64, error) {
+	if err := ctxErr(p.ctx); err != nil {
+		return nil, err
+	}
+	if len(p.elements) == 0 {
+		return nil, nil
+	}
+	sum, err := parallelSummate(p.ctx, p.parallelism, p.elements)
+	if err != nil {
+		return nil, err
+	}
+	avg := sum.asFloat64() / float64(len(p.elements))
+	return &avg, nil
+}
+
+func (p *parallelNumberStream[N]) Collect() ([]N, error) {
+	if err := ctxErr(p.ctx); err != nil {
+		return nil, err
+	}
+	res := make([]N, len(p.elements))
+	copy(res, p.elements)
+	return res, nil
+}
+
+func (p *parallelNumberStream[N]) Distinct() NumberStream[N] {
+	if len(p.elements) <= 1 {
+		return p
+	}
+	return p.with(distinctNumbers(p.elements))
+}
+
+func (p *parallelNumberStream[N]) Err() error {
+	return ctxErr(p.ctx)
+}
+
+func (p *parallelNumberStream[N]) DropWhile(predicate func(val N) (match bool)) NumberStream[N] {
+	n, err := matchingPrefix(p.ctx, "DropWhile", p.parallelism, 0, p.elements, predicate)
+	if err != nil {
+		return &errNumberStream[N]{err: err, parallel: true}
+	}
+	if n == 0 {
+		return p
+	}
+	return p.with(p.elements[n:])
+}
+
+func (p *parallelNumberStream[N]) Filter(predicate func(val N) (keep bool)) NumberStream[N] {
+	if len(p.elements) == 0 {
+		return p
+	}
+	matches := make([]bool, len(p.elements))
+	err := parallelEach(p.ctx, "Filter", p.parallelism, len(p.elements), func(i int) {
+		matches[i] = predicate(p.elements[i])
+	})
+	if err != nil {
+		return &errNumberStream[N]{err: err, parallel: true}
+	}
+	return p.with(filterNumbers(p.elements, matches))
+}
+
+func (p *parallelNumberStream[N]) FlatMap(mapper func(val N) NumberStream[N]) NumberStream[N] {
+	if len(p.elements) == 0 {
+		return p
+	}
+	streams := make([]NumberStream[N], len(p.elements))
+	err := parallelEach(p.ctx, "FlatMap", p.parallelism, len(p.elements), func(i int) {
+		streams[i] = mapper(p.elements[i])
+	})
+	if err != nil {
+		return &errNumberStream[N]{err: err, parallel: true}
+	}
+	newElements, err := concatNumbers(streams)
+	if err != nil {
+		return &errNumberStream[N]{err: err, parallel: true}
+	}
+	return p.with(newElements)
+}
+
+func (p *parallelNumberStream[N]) Limit(maxSize int) NumberStream[N] {
+	if maxSize < 0 {
+		return &errNumberStream[N]{err: fmt.Errorf("limit error, maxSize is negative: %d", maxSize), parallel: true}
+	}
+	if maxSize >= len(p.elements) {
+		return p
+	}
+	return p.with(p.elements[:maxSize])
+}
+
+func (p *parallelNumberStream[N]) Map(mapper func(src N) (dest N)) NumberStream[N] {
+	if len(p.elements) == 0 {
+		return p
+	}
+	newElements := make([]N, len(p.elements))
+	err := parallelEach(p.ctx, "Map", p.parallelism, len(p.elements), func(i int) {
+		newElements[i] = mapper(p.elements[i])
+	})
+	if err != nil {
+		return &errNumberStream[N]{err: err, parallel: true}
+	}
+	return p.with(newElements)
+}
+
+func (p *parallelNumberStream[N]) MapToFloat64(mapper func(src N) (dest float64)) Float64Stream {
+	return parallelMapToNumber(p, "MapToFloat64", mapper)
+}
+
+func (p *parallelNumberStream[N]) MapToInt(mapper func(src N) (dest int)) IntStream {
+	return parallelMapToNumber(p, "MapToInt", mapper)
+}
+
+func (p *parallelNumberStream[N]) MapToInt64(mapper func(src N) (dest int64)) Int64Stream {
+	return parallelMapToNumber(p, "MapToInt64", mapper)
+}
+
+func (p *parallelNumberStream[N]) MapToString(mapper func(src N) (dest string)) StringStream {
+	return newStringStream(p.MapToObj(func(src N) (dest interface{}) {
+		return mapper(src)
+	}))
+}
+
+func (p *parallelNumberStream[N]) MapToObj(mapper func(src N) (dest interface{})) Stream {
+	if len(p.elements) == 0 {
+		return newParallelStream(p.config, nil)
+	}
+	objs := make([]*element, len(p.elements))
+	err := parallelEach(p.ctx, "MapToObj", p.parallelism, len(p.elements), func(i int) {
+		objs[i] = newElement(mapper(p.elements[i]))
+	})
+	if err != nil {
+		return &errStream{err: err, parallel: true}
+	}
+	return newParallelStream(p.config, sliceStage(objs))
+}
+
+func (p *parallelNumberStream[N]) Max() (*N, error) {
+	return p.extreme("Max", func(a, b N) bool {
+		return a > b
+	})
+}
+
+func (p *parallelNumberStream[N]) Min() (*N, error) {
+	return p.extreme("Min", func(a, b N) bool {
+		return a < b
+	})
+}
+
+// extreme reduces the elements to the one that no other element is better than by the fork/join pool. Like the
+// sequential stream, a NaN is only the result if it's the first element, since it isn't comparable to the others.
+func (p *parallelNumberStream[N]) extreme(op string, better func(a, b N) bool) (*N, error) {
+	if len(p.elements) == 0 {
+		return nil, ctxErr(p.ctx)
+	}
+	result, err := p.reduce(op, nil, func(a, b N) (c N) {
+		if isNaN(a) || better(b, a) {
+			return b
+		}
+		return a
+	})
+	if err != nil {
+		return nil, err
+	}
+	if first := p.elements[0]; isNaN(first) {
+		result = first
+	}
+	return &result, nil
+}
+
+func (p *parallelNumberStream[N]) Parallel() NumberStream[N] {
+	return p
+}
+
+func (p *parallelNumberStream[N]) Reduce(op func(a, b N) (c N)) (*N, error) {
+	if len(p.elements) == 0 {
+		return nil, ctxErr(p.ctx)
+	}
+	result, err := p.reduce("Reduce", nil, op)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (p *parallelNumberStream[N]) ReduceWithIdentity(identity N, op func(a, b N) (c N)) (N, error) {
+	if len(p.elements) == 0 {
+		if err := ctxErr(p.ctx); err != nil {
+			var zero N
+			return zero, err
+		}
+		return identity, nil
+	}
+	return p.reduce("ReduceWithIdentity", &identity, op)
+}
+
+// reduce reduces the non-empty elements with op by the fork/join pool, each partition starts from identity if it's
+// not nil. op is the name of the operation reported by the *PanicError.
+func (p *parallelNumberStream[N]) reduce(op string, identity *N, accumulator func(a, b N) (c N)) (N, error) {
+	var result N
+	if err := ctxErr(p.ctx); err != nil {
+		return result, err
+	}
+	task := newNumberReduceRecursiveTask(op, identity, accumulator, p.elements, 0, len(p.elements)-1)
+	err := guard(op, -1, func() {
+		result = DefaultForkJoinPool().Invoke(task).(N)
+	})
+	if err == nil {
+		err = ctxErr(p.ctx)
+	}
+	if err != nil {
+		var zero N
+		return zero, err
+	}
+	return result, nil
+}
+
+func (p *parallelNumberStream[N]) Sequential() NumberStream[N] {
+	return &sequentialNumberStream[N]{elements: p.elements, config: p.config}
+}
+
+func (p *parallelNumberStream[N]) Skip(n int) NumberStream[N] {
+	if n < 0 {
+		return &errNumberStream[N]{err: fmt.Errorf("skip error, n is negative: %v", n), parallel: true}
+	}
+	if n == 0 {
+		return p
+	}
+	if n >= len(p.elements) {
+		return p.with(nil)
+	}
+	return p.with(p.elements[n:])
+}
+
+func (p *parallelNumberStream[N]) Sorted() NumberStream[N] {
+	if len(p.elements) <= 1 || numbersAreSorted(p.elements) {
+		return p
+	}
+	return p.with(sortNumbers(p.elements))
+}
+
+func (p *parallelNumberStream[N]) SortedDescending() NumberStream[N] {
+	return p.SortedWith(greaterNumber[N])
+}
+
+func (p *parallelNumberStream[N]) SortedWith(less func(a, b N) bool) NumberStream[N] {
+	sorted, err := sortNumbersWith(p.elements, less)
+	if err != nil {
+		return &errNumberStream[N]{err: err, parallel: true}
+	}
+	return p.with(sorted)
+}
+
+func (p *parallelNumberStream[N]) TakeWhile(predicate func(val N) (match bool)) NumberStream[N] {
+	n, err := matchingPrefix(p.ctx, "TakeWhile", p.parallelism, 0, p.elements, predicate)
+	if err != nil {
+		return &errNumberStream[N]{err: err, parallel: true}
+	}
+	if n == len(p.elements) {
+		return p
+	}
+	return p.with(p.elements[:n])
+}
+
+func (p *parallelNumberStream[N]) Sum() (N, error) {
+	sum, err := parallelSummate(p.ctx, p.parallelism, p.elements)
+	if err != nil {
+		return 0, err
+	}
+	return sum.value(), nil
+}
+
+func (p *parallelNumberStream[N]) SumChecked() (N, error) {
+	sum, err := parallelSummate(p.ctx, p.parallelism, p.elements)
+	if err != nil {
+		return 0, err
+	}
+	return sum.checked()
+}
+
+func (p *parallelNumberStream[N]) WithContext(ctx context.Context) NumberStream[N] {
+	return &parallelNumberStream[N]{elements: p.elements, config: p.withContext(ctx)}
+}
+
+func (p *parallelNumberStream[N]) WithParallelism(n int) NumberStream[N] {
+	if n <= 0 {
+		return &errNumberStream[N]{err: fmt.Errorf("parallelism error, n is not positive: %v", n), parallel: true}
+	}
+	return &parallelNumberStream[N]{elements: p.elements, config: p.withParallelism(n)}
+}
+
+func (p *parallelNumberStream[N]) FilterE(predicate func(val N) (match bool, err error)) NumberStream[N] {
+	if len(p.elements) == 0 {
+		return p
+	}
+	matches := make([]bool, len(p.elements))
+	err := p.eachE("FilterE", true, len(p.elements), func(i int) (err error) {
+		matches[i], err = predicate(p.elements[i])
+		return err
+	})
+	if err != nil {
+		return &errNumberStream[N]{err: err, parallel: true}
+	}
+	return p.with(filterNumbers(p.elements, matches))
+}
+
+func (p *parallelNumberStream[N]) FlatMapE(mapper func(val N) (NumberStream[N], error)) NumberStream[N] {
+	if len(p.elements) == 0 {
+		return p
+	}
+	streams := make([]NumberStream[N], len(p.elements))
+	err := p.eachE("FlatMapE", true, len(p.elements), func(i int) (err error) {
+		streams[i], err = mapper(p.elements[i])
+		return err
+	})
+	if err != nil {
+		return &errNumberStream[N]{err: err, parallel: true}
+	}
+	newElements, err := concatNumbers(streams)
+	if err != nil {
+		return &errNumberStream[N]{err: err, parallel: true}
+	}
+	return p.with(newElements)
+}
+
+func (p *parallelNumberStream[N]) MapE(mapper func(src N) (dest N, err error)) NumberStream[N] {
+	if len(p.elements) == 0 {
+		return p
+	}
+	newElements := make([]N, len(p.elements))
+	err := p.eachE("MapE", true, len(p.elements), func(i int) (err error) {
+		newElements[i], err = mapper(p.elements[i])
+		return err
+	})
+	if err != nil {
+		return &errNumberStream[N]{err: err, parallel: true}
+	}
+	return p.with(newElements)
+}
+
+func (p *parallelNumberStream[N]) WithErrorMode(mode ErrorMode) NumberStream[N] {
+	if err := checkErrorMode(mode); err != nil {
+		return &errNumberStream[N]{err: err, parallel: true}
+	}
+	return &parallelNumberStream[N]{elements: p.elements, config: p.withErrorMode(mode)}
+}
+
+func (e *errNumberStream[N]) MapToObj(func(src N) (dest interface{})) Stream {
+	return &errStream{err: e.err, parallel: e.parallel}
+}
+
+func (e *errNumberStream[N]) Err() error {
+	return e.err
+}
+
+func (e *errNumberStream[N]) IsParallel() bool {
+	return e.parallel
+}
+
+func (e *errNumberStream[N]) Average() (*float64, error) {
+	return nil, e.err
+}
+
+func (e *errNumberStream[N]) Collect() ([]N, error) {
+	return nil, e.err
+}
+
+func (e *errNumberStream[N]) Distinct() NumberStream[N] {
+	return e
+}
+
+func (e *errNumberStream[N]) DropWhile(func(val N) (match bool)) NumberStream[N] {
+	return e
+}
+
+func (e *errNumberStream[N]) Filter(func(val N) (keep bool)) NumberStream[N] {
+	return e
+}
+
+func (e *errNumberStream[N]) FlatMap(func(val N) NumberStream[N]) NumberStream[N] {
+	return e
+}
+
+func (e *errNumberStream[N]) Limit(int) NumberStream[N] {
+	return e
+}
+
+func (e *errNumberStream[N]) Map(func(src N) (dest N)) NumberStream[N] {
+	return e
+}
+
+func (e *errNumberStream[N]) MapToFloat64(func(src N) (dest float64)) Float64Stream {
+	return &errFloat64Stream{err: e.err, parallel: e.parallel}
+}
+
+func (e *errNumberStream[N]) MapToInt(func(src N) (dest int)) IntStream {
+	return &errIntStream{err: e.err, parallel: e.parallel}
+}
+
+func (e *errNumberStream[N]) MapToInt64(func(src N) (dest int64)) Int64Stream {
+	return &errInt64Stream{err: e.err, parallel: e.parallel}
+}
+
+func (e *errNumberStream[N]) MapToString(func(src N) (dest string)) StringStream {
+	return newStringStream(&errStream{err: e.err, parallel: e.parallel})
+}
+
+func (e *errNumberStream[N]) Max() (*N, error) {
+	return nil, e.err
+}
+
+func (e *errNumberStream[N]) Min() (*N, error) {
+	return nil, e.err
+}
+
+func (e *errNumberStream[N]) Parallel() NumberStream[N] {
+	if e.parallel {
+		return e
+	}
+	return &errNumberStream[N]{err: e.err, parallel: true}
+}
+
+func (e *errNumberStream[N]) Reduce(func(a, b N) (c N)) (*N, error) {
+	return nil, e.err
+}
+
+func (e *errNumberStream[N]) Sequential() NumberStream[N] {
+	if e.parallel {
+		return &errNumberStream[N]{err: e.err, parallel: false}
+	}
+	return e
+}
+
+func (e *errNumberStream[N]) Skip(int) NumberStream[N] {
+	return e
+}
+
+func (e *errNumberStream[N]) ReduceWithIdentity(N, func(a, b N) (c N)) (N, error) {
+	var zero N
+	return zero, e.err
+}
+
+func (e *errNumberStream[N]) Sorted() NumberStream[N] {
+	return e
+}
+
+func (e *errNumberStream[N]) SortedDescending() NumberStream[N] {
+	return e
+}
+
+func (e *errNumberStream[N]) SortedWith(func(a, b N) bool) NumberStream[N] {
+	return e
+}
+
+func (e *errNumberStream[N]) TakeWhile(func(val N) (match bool)) NumberStream[N] {
+	return e
+}
+
+func (e *errNumberStream[N]) SumChecked() (N, error) {
+	return 0, e.err
+}
+
+func (e *errNumberStream[N]) Sum() (N, error) {
+	return 0, e.err
+}
+
+func (e *errNumberStream[N]) WithContext(context.Context) NumberStream[N] {
+	return e
+}
+
+func (e *errNumberStream[N]) WithParallelism(int) NumberStream[N] {
+	return e
+}
+
+func (e *errNumberStream[N]) FilterE(func(val N) (match bool, err error)) NumberStream[N] {
+	return e
+}
+
+func (e *errNumberStream[N]) FlatMapE(func(val N) (NumberStream[N], error)) NumberStream[N] {
+	return e
+}
+
+func (e *errNumberStream[N]) MapE(func(src N) (dest N, err error)) NumberStream[N] {
+	return e
+}
+
+func (e *errNumberStream[N]) WithErrorMode(ErrorMode) NumberStream[N] {
+	return e
+}
+
+// sequentialMapToNumber returns a sequential stream consisting of the results of applying mapper to the elements of
+// s, op is the name of the operation reported by the *PanicError.
+func sequentialMapToNumber[N, M Number](s *sequentialNumberStream[N], op string, mapper func(src N) (dest M)) NumberStream[M] {
+	if len(s.elements) == 0 {
+		return &sequentialNumberStream[M]{config: s.config}
+	}
+	newElements := make([]M, len(s.elements))
+	err := sequentialEach(s.ctx, op, len(s.elements), func(i int) {
+		newElements[i] = mapper(s.elements[i])
+	})
+	if err != nil {
+		return &errNumberStream[M]{err: err}
+	}
+	return &sequentialNumberStream[M]{elements: newElements, config: s.config}
+}
+
+// parallelMapToNumber returns a parallel stream consisting of the results of applying mapper to the elements of p
+// concurrently, op is the name of the operation reported by the *PanicError.
+func parallelMapToNumber[N, M Number](p *parallelNumberStream[N], op string, mapper func(src N) (dest M)) NumberStream[M] {
+	if len(p.elements) == 0 {
+		return &parallelNumberStream[M]{config: p.config}
+	}
+	newElements := make([]M, len(p.elements))
+	err := parallelEach(p.ctx, op, p.parallelism, len(p.elements), func(i int) {
+		newElements[i] = mapper(p.elements[i])
+	})
+	if err != nil {
+		return &errNumberStream[M]{err: err, parallel: true}
+	}
+	return &parallelNumberStream[M]{elements: newElements, config: p.config}
+}
+
+func distinctNumbers[N Number](numbers []N) []N {
+	seen := make(map[N]bool)
+	remain := make([]N, 0)
+	for _, e := range numbers {
+		if !seen[e] {
+			seen[e] = true
+			remain = append(remain, e)
+		}
+	}
+	return remain
+}
+
+// filterNumbers returns the numbers whose matches are true, in encounter order.
+func filterNumbers[N Number](numbers []N, matches []bool) []N {
+	remain := make([]N, 0)
+	for i, match := range matches {
+		if match {
+			remain = append(remain, numbers[i])
+		}
+	}
+	return remain
+}
+
+// concatNumbers collects the elements of streams in order, the first error occurred is returned.
+func concatNumbers[N Number](streams []NumberStream[N]) ([]N, error) {
+	newElements := make([]N, 0)
+	for _, stream := range streams {
+		elements, err := stream.Collect()
+		if err != nil {
+			return nil, err
+		}
+		newElements = append(newElements, elements...)
+	}
+	return newElements, nil
+}
+
+// lessNumber reports whether a is less than b, NaN is considered less than any other value like sort.Float64s does.
+func lessNumber[N Number](a, b N) bool {
+	return a < b || isNaN(a) && !isNaN(b)
+}
+
+func isNaN[N Number](n N) bool {
+	return n != n
+}
+
+func numbersAreSorted[N Number](numbers []N) bool {
+	for i := len(numbers) - 1; i > 0; i-- {
+		if lessNumber(numbers[i], numbers[i-1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortNumbers returns a sorted copy of numbers.
+func sortNumbers[N Number](numbers []N) []N {
+	sorted := make([]N, len(numbers))
+	copy(sorted, numbers)
+	sort.Slice(sorted, func(i, j int) bool {
+		return lessNumber(sorted[i], sorted[j])
+	})
+	return sorted
+}
+
+// sortNumbersWith returns a copy of numbers stably sorted according to less, the panic of less is returned as a
+// *PanicError.
+func sortNumbersWith[N Number](numbers []N, less func(a, b N) bool) ([]N, error) {
+	sorted := make([]N, len(numbers))
+	copy(sorted, numbers)
+	err := guard("SortedWith", -1, func() {
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return less(sorted[i], sorted[j])
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sorted, nil
+}
+
+func greaterNumber[N Number](a, b N) bool {
+	return lessNumber(b, a)
+}
@@ -0,0 +1,127 @@
+package gostream
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestNumberStream(t *testing.T) {
+	t.Run("test int64", func(t *testing.T) {
+		for _, s := range []NumberStream[int64]{
+			NewSequentialNumberStream([]int64{3, math.MaxInt64 - 3, 3, 1}),
+			NewParallelNumberStream([]int64{3, math.MaxInt64 - 3, 3, 1}),
+		} {
+			res, err := s.Distinct().Sorted().Collect()
+			assert.NoError(t, err)
+			assert.Equal(t, []int64{1, 3, math.MaxInt64 - 3}, res)
+			sum, err := s.Limit(2).Sum()
+			assert.NoError(t, err)
+			assert.Equal(t, int64(math.MaxInt64), sum)
+			max, err := s.Max()
+			assert.NoError(t, err)
+			assert.Equal(t, int64(math.MaxInt64-3), *max)
+		}
+	})
+
+	t.Run("test min and max", func(t *testing.T) {
+		nan := math.NaN()
+		large := make([]float64, 5*DefaultForkJoinThreshold)
+		for i := range large {
+			large[i] = float64((i * 7919) % len(large))
+		}
+		large[len(large)/2] = nan
+		large[len(large)-1] = nan
+		for _, tt := range []struct {
+			elements []float64
+			min, max float64
+		}{
+			{elements: large, min: 0, max: float64(len(large) - 1)},
+			{elements: append([]float64{nan}, large...), min: nan, max: nan},
+			{elements: []float64{2.5, nan, -1}, min: -1, max: 2.5},
+		} {
+			for _, s := range []NumberStream[float64]{
+				NewSequentialNumberStream(tt.elements),
+				NewParallelNumberStream(tt.elements),
+			} {
+				min, err := s.Min()
+				assert.NoError(t, err)
+				assert.Equal(t, isNaN(tt.min), isNaN(*min))
+				if !isNaN(tt.min) {
+					assert.Equal(t, tt.min, *min)
+				}
+				max, err := s.Max()
+				assert.NoError(t, err)
+				assert.Equal(t, isNaN(tt.max), isNaN(*max))
+				if !isNaN(tt.max) {
+					assert.Equal(t, tt.max, *max)
+				}
+			}
+		}
+	})
+
+	t.Run("test float32", func(t *testing.T) {
+		nan := float32(math.NaN())
+		for _, s := range []NumberStream[float32]{
+			NewSequentialNumberStream([]float32{2.5, nan, -1}),
+			NewParallelNumberStream([]float32{2.5, nan, -1}),
+		} {
+			res, err := s.Sorted().Skip(1).Collect()
+			assert.NoError(t, err)
+			assert.Equal(t, []float32{-1, 2.5}, res)
+			avg, err := s.Filter(func(val float32) (match bool) {
+				return !isNaN(val)
+			}).Average()
+			assert.NoError(t, err)
+			assert.Equal(t, 0.75, *avg)
+		}
+	})
+
+	t.Run("test named type", func(t *testing.T) {
+		type celsius float64
+		reduced, err := NewParallelNumberStream([]celsius{1, 2, 3}).Reduce(func(a, b celsius) (c celsius) {
+			return a + b
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, celsius(6), *reduced)
+	})
+
+	t.Run("test map to other numbers", func(t *testing.T) {
+		for _, s := range []NumberStream[uint8]{
+			NewSequentialNumberStream([]uint8{1, 2}),
+			NewParallelNumberStream([]uint8{1, 2}),
+		} {
+			ints, err := s.MapToInt(func(src uint8) (dest int) {
+				return -int(src)
+			}).Collect()
+			assert.NoError(t, err)
+			assert.Equal(t, []int{-1, -2}, ints)
+			float64s, err := s.MapToFloat64(func(src uint8) (dest float64) {
+				return float64(src) / 2
+			}).Collect()
+			assert.NoError(t, err)
+			assert.Equal(t, []float64{0.5, 1}, float64s)
+		}
+	})
+
+	t.Run("test concat", func(t *testing.T) {
+		res, err := ConcatNumberStream(NewSequentialNumberStream([]int8{1}), NewParallelNumberStream([]int8{2})).Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []int8{1, 2}, res)
+		_, err = ConcatNumberStream[int8](NewSequentialNumberStream([]int8{1}), &errNumberStream[int8]{err: errors.New("")}).Collect()
+		assert.Error(t, err)
+	})
+
+	t.Run("test from chan", func(t *testing.T) {
+		ch := make(chan uint)
+		go func() {
+			defer close(ch)
+			ch <- 1
+			ch <- 2
+		}()
+		res, err := NewNumberStreamFromChan(ch).Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []uint{1, 2}, res)
+	})
+}
@@ -0,0 +1,98 @@
+package gostream
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is the error reported when a function supplied to a stream operation panics, the stream becomes an
+// error stream reporting it by Err and the terminal operations.
+type PanicError struct {
+	// Op is the name of the operation the function was supplied to, such as "Map".
+	Op string
+	// Index is the index of the element the function was applied to, counted from the input of the operation.
+	// It's -1 if the function wasn't applied to a single element, such as a comparator.
+	Index int
+	// Value is the value passed to panic.
+	Value interface{}
+	// Stack is the stack trace of the goroutine when it panicked.
+	Stack []byte
+}
+
+func (p *PanicError) Error() string {
+	if p.Index < 0 {
+		return fmt.Sprintf("panic in %s: %v\n%s", p.Op, p.Value, p.Stack)
+	}
+	return fmt.Sprintf("panic in %s at element %d: %v\n%s", p.Op, p.Index, p.Value, p.Stack)
+}
+
+// Unwrap returns the value passed to panic if it's an error.
+func (p *PanicError) Unwrap() error {
+	err, _ := p.Value.(error)
+	return err
+}
+
+// newPanicError returns the *PanicError of the value r recovered from the function of op applied to the element at
+// index. r is returned as is if it's already a *PanicError, which was raised by a nested stream.
+func newPanicError(op string, index int, r interface{}) *PanicError {
+	if p, ok := r.(*PanicError); ok {
+		return p
+	}
+	return &PanicError{Op: op, Index: index, Value: r, Stack: debug.Stack()}
+}
+
+// catch panics again the value panicked by the function of op applied to the element at index as a *PanicError, so
+// that the stream recovering it knows where it came from. It must be deferred directly.
+func catch(op string, index int) {
+	if r := recover(); r != nil {
+		panic(newPanicError(op, index, r))
+	}
+}
+
+// guard calls f, the value panicked by f is returned as the *PanicError of op at index.
+func guard(op string, index int, f func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newPanicError(op, index, r)
+		}
+	}()
+	f()
+	return nil
+}
+
+// guardAt calls f, the value panicked by f is returned as the *PanicError of op at the index pointed by index when f
+// panicked, f updates it while walking through the elements.
+func guardAt(op string, index *int, f func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newPanicError(op, *index, r)
+		}
+	}()
+	f()
+	return nil
+}
+
+// sequentialEach calls f with each index in [0, n) in order, it stops once ctx is done or f panics.
+// The error of ctx or the *PanicError of op is returned.
+func sequentialEach(ctx context.Context, op string, n int, f func(i int)) error {
+	return sequentialEachAt(ctx, op, 0, n, f)
+}
+
+// sequentialEachAt is the sequentialEach of the batch of elements starting at offset of the stream, offset is added
+// to the index reported by the *PanicError.
+func sequentialEachAt(ctx context.Context, op string, offset, n int, f func(i int)) (err error) {
+	i := 0
+	defer func() {
+		if r := recover(); r != nil {
+			err = newPanicError(op, offset+i, r)
+		}
+	}()
+	for ; i < n; i++ {
+		if err := ctxErr(ctx); err != nil {
+			return err
+		}
+		f(i)
+	}
+	return nil
+}
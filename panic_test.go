@@ -0,0 +1,167 @@
+package gostream
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func assertPanicError(t *testing.T, err error, op string, index int) {
+	var p *PanicError
+	if assert.True(t, errors.As(err, &p), "%v is not a *PanicError", err) {
+		assert.Equal(t, op, p.Op)
+		assert.Equal(t, index, p.Index)
+		assert.Equal(t, "boom", p.Value)
+		assert.NotEmpty(t, p.Stack)
+		assert.Contains(t, p.Error(), "boom")
+	}
+}
+
+func panicAt(index int) func(i int) {
+	return func(i int) {
+		if i == index {
+			panic("boom")
+		}
+	}
+}
+
+func TestPanicError(t *testing.T) {
+	cause := errors.New("cause")
+	p := newPanicError("Map", 1, cause)
+	assert.True(t, errors.Is(p, cause))
+	assert.Contains(t, p.Error(), "panic in Map at element 1: cause")
+	assert.Same(t, p, newPanicError("Filter", 2, p))
+	assert.Nil(t, newPanicError("Sorted", -1, "boom").Unwrap())
+	assert.Contains(t, newPanicError("Sorted", -1, "boom").Error(), "panic in Sorted: boom")
+}
+
+func TestStreamPanic(t *testing.T) {
+	boom := panicAt(2)
+	for _, newStream := range []func([]*element) Stream{newSequentialStreamForTest, newParallelStreamForTest} {
+		ints := intSliceToElements([]int{0, 1, 2, 3})
+		tests := []struct {
+			name  string
+			s     Stream
+			index int
+		}{
+			{"Filter", newStream(ints).Filter(func(val interface{}) (match bool) {
+				boom(val.(int))
+				return true
+			}), 2},
+			{"Map", newStream(ints).Map(func(src interface{}) (dest interface{}) {
+				boom(src.(int))
+				return src
+			}), 2},
+			{"FlatMap", newStream(ints).FlatMap(func(val interface{}) Stream {
+				boom(val.(int))
+				return NewSequentialStream([]int{val.(int)})
+			}), 2},
+			{"Distinct", newStream(ints).Distinct(func(obj interface{}) interface{} {
+				boom(obj.(int))
+				return obj
+			}, func(a, b interface{}) bool {
+				return a == b
+			}), 2},
+			{"Sorted", newStream(ints).Sorted(func(a, b interface{}) bool {
+				panic("boom")
+			}), -1},
+			{"Map", newStream(ints).Skip(1).Map(func(src interface{}) (dest interface{}) {
+				boom(src.(int))
+				return src
+			}), 1},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var res []int
+				err := tt.s.Collect(&res)
+				assertPanicError(t, err, tt.name, tt.index)
+				assertPanicError(t, tt.s.Err(), tt.name, tt.index)
+			})
+		}
+
+		_, err := newStream(ints).Reduce(func(a, b interface{}) (c interface{}) {
+			boom(b.(int))
+			return b
+		})
+		assertPanicError(t, err, "Reduce", 2)
+		_, err = newStream(ints).ReduceTo(0, func(acc, val interface{}) interface{} {
+			boom(val.(int))
+			return acc
+		}, func(a, b interface{}) interface{} {
+			return a
+		})
+		assertPanicError(t, err, "ReduceTo", 2)
+
+		intStream := newStream(ints).MapToInt(func(src interface{}) (dest int) {
+			boom(src.(int))
+			return 0
+		})
+		_, err = intStream.Sum()
+		assertPanicError(t, err, "MapToInt", 2)
+		assertPanicError(t, intStream.Err(), "MapToInt", 2)
+		_, err = newStream(ints).MapToFloat64(func(src interface{}) (dest float64) {
+			boom(src.(int))
+			return 0
+		}).Sum()
+		assertPanicError(t, err, "MapToFloat64", 2)
+	}
+}
+
+func TestIntStreamPanic(t *testing.T) {
+	boom := panicAt(2)
+	for _, s := range []IntStream{NewSequentialIntStream([]int{0, 1, 2, 3}), NewParallelIntStream([]int{0, 1, 2, 3})} {
+		assertPanicError(t, s.Map(func(src int) (dest int) {
+			boom(src)
+			return src
+		}).Err(), "Map", 2)
+		assertPanicError(t, s.Filter(func(val int) (match bool) {
+			boom(val)
+			return true
+		}).Err(), "Filter", 2)
+		assertPanicError(t, s.FlatMap(func(val int) IntStream {
+			boom(val)
+			return NewSequentialIntStream([]int{val})
+		}).Err(), "FlatMap", 2)
+		assertPanicError(t, s.MapToObj(func(src int) (dest interface{}) {
+			boom(src)
+			return src
+		}).Err(), "MapToObj", 2)
+		assertPanicError(t, s.MapToFloat64(func(src int) (dest float64) {
+			boom(src)
+			return 0
+		}).Err(), "MapToFloat64", 2)
+		_, err := s.Reduce(func(a, b int) (c int) {
+			boom(b)
+			return b
+		})
+		assertPanicError(t, err, "Reduce", 2)
+		_, err = s.ReduceWithIdentity(0, func(a, b int) (c int) {
+			boom(b)
+			return b
+		})
+		assertPanicError(t, err, "ReduceWithIdentity", 2)
+	}
+}
+
+func TestFloat64StreamPanic(t *testing.T) {
+	boom := panicAt(2)
+	for _, s := range []Float64Stream{NewSequentialFloat64Stream([]float64{0, 1, 2, 3}), NewParallelFloat64Stream([]float64{0, 1, 2, 3})} {
+		assertPanicError(t, s.Map(func(src float64) (dest float64) {
+			boom(int(src))
+			return src
+		}).Err(), "Map", 2)
+		assertPanicError(t, s.Filter(func(val float64) (match bool) {
+			boom(int(val))
+			return true
+		}).Err(), "Filter", 2)
+		assertPanicError(t, s.MapToInt(func(src float64) (dest int) {
+			boom(int(src))
+			return 0
+		}).Err(), "MapToInt", 2)
+		_, err := s.Reduce(func(a, b float64) (c float64) {
+			boom(int(b))
+			return b
+		})
+		assertPanicError(t, err, "Reduce", 2)
+	}
+}
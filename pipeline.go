@@ -0,0 +1,889 @@
+package gostream
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// stage is a lazily evaluated step of a stream pipeline, nothing is computed until a terminal operation opens it
+// and pulls elements from the returned iterator. A nil stage produces no elements.
+// ctx is passed to the upstream stages when opening, the sources and the parallel stages stop working once ctx is
+// done, and the error of ctx is reported by the iterator.
+type stage func(ctx context.Context) iterator
+
+// iterator pulls the elements of a stage one at a time.
+type iterator interface {
+	// next returns the next element, ok is false when there is no more element or an error occurred.
+	next() (e *element, ok bool)
+	// err returns the error occurred while pulling elements, nil is returned if no error occurred.
+	err() error
+}
+
+// pipeline is the chain of stages behind a stream, tail is the last stage of the chain.
+// ctx is the context the stages are evaluated with, context.Background is used if it's nil.
+type pipeline struct {
+	tail stage
+	// source is the elements of the slice the pipeline consists of, it's nil if the pipeline has a stage other than the
+	// slice source, so that the size of the pipeline is known without evaluation.
+	source []*element
+	config
+
+	mu      sync.Mutex
+	lastErr error
+}
+
+type emptyIterator struct{}
+
+type errIterator struct {
+	e error
+}
+
+type sliceIterator struct {
+	ctx      context.Context
+	elements []*element
+	index    int
+	e        error
+}
+
+type filterIterator struct {
+	iterator
+	predicate func(val interface{}) (match bool)
+	index     int
+}
+
+type mapIterator struct {
+	iterator
+	// op is the name of the operation reported when mapper panics.
+	op     string
+	mapper func(src interface{}) (dest interface{})
+	index  int
+}
+
+type peekIterator struct {
+	iterator
+	action func(val interface{})
+	index  int
+}
+
+// filterEIterator is the filterIterator whose predicate may fail, the failed elements are dropped.
+type filterEIterator struct {
+	iterator
+	predicate func(val interface{}) (match bool, err error)
+	index     int
+	sink      errorSink
+	stopped   bool
+}
+
+// mapEIterator is the mapIterator whose mapper may fail, the failed elements are dropped.
+type mapEIterator struct {
+	iterator
+	mapper  func(src interface{}) (dest interface{}, err error)
+	index   int
+	sink    errorSink
+	stopped bool
+}
+
+type limitIterator struct {
+	iterator
+	remain int
+}
+
+type skipIterator struct {
+	iterator
+	n int
+}
+
+// whileIterator produces the leading elements of its upstream matching predicate for TakeWhile, or the remaining
+// elements after them for DropWhile. The upstream elements are pulled and tested in batches, a sequential stream
+// pulls a single element at a time, so no element after the first mismatch is pulled.
+type whileIterator struct {
+	iterator
+	ctx         context.Context
+	op          string
+	predicate   func(val interface{}) (match bool)
+	parallelism int
+	take        bool
+	pending     []*element
+	index       int
+	mismatched  bool
+	e           error
+}
+
+type distinctIterator struct {
+	iterator
+	hashcode func(obj interface{}) interface{}
+	equals   func(a, b interface{}) bool
+	seen     map[interface{}][]interface{}
+	index    int
+}
+
+type flatMapIterator struct {
+	upstream iterator
+	// op is the name of the operation reported when mapper panics.
+	op      string
+	mapper  func(val interface{}) (Stream, error)
+	current iterator
+	index   int
+	sink    errorSink
+	e       error
+}
+
+// guardIterator turns the panic raised while pulling the elements into a *PanicError reported by err, so that the
+// panic of a function supplied to a stream never escapes the terminal operation.
+type guardIterator struct {
+	iterator
+	e error
+}
+
+type concatIterator struct {
+	ctx       context.Context
+	iterators []iterator
+	e         error
+}
+
+// windowIterator produces the windows of size consecutive upstream elements starting every step elements, the
+// trailing window with fewer elements is produced only if partial is true.
+type windowIterator struct {
+	iterator
+	size    int
+	step    int
+	partial bool
+	window  []interface{}
+	started bool
+	done    bool
+}
+
+type zipIterator struct {
+	ctx      context.Context
+	first    iterator
+	second   iterator
+	combiner func(first, second interface{}) interface{}
+	index    int
+	done     bool
+	e        error
+}
+
+// barrierIterator drains its upstream before producing any element, it's used by the operations which need to see
+// all the elements, such as sorting, joining or grouping.
+type barrierIterator struct {
+	upstream iterator
+	ctx      context.Context
+	compute  func(ctx context.Context, elements []*element) ([]*element, error)
+	done     bool
+	sliceIterator
+	e error
+}
+
+// batchIterator pulls its upstream in batches and produces the elements computed from each batch before pulling the
+// next one, it's used by the parallel operations computing each element on its own, so that an infinite stream is
+// processed in bounded memory. The batches grow from firstBatchSize(parallelism) to maxBatchSize, so that a short
+// stream like a limited one is not pulled much further than needed.
+type batchIterator struct {
+	upstream iterator
+	ctx      context.Context
+	// compute computes the elements of batch, offset is the index of its first element in the upstream.
+	compute func(ctx context.Context, offset int, batch []*element) ([]*element, error)
+	size    int
+	offset  int
+	pending []*element
+	done    bool
+	e       error
+}
+
+// iterator opens the pipeline and returns an iterator pulling the elements produced by its tail stage.
+func (p *pipeline) iterator() iterator {
+	return &guardIterator{iterator: openStage(p.context(), p.tail)}
+}
+
+// within opens the pipeline as a source of a stream evaluated with ctx, and returns an iterator pulling the elements
+// produced by its tail stage. The stages are opened with ctx unless the pipeline has its own context, so that the
+// context of the stream reaches the sources of the pipeline.
+func (p *pipeline) within(ctx context.Context) iterator {
+	if p.ctx != nil {
+		ctx = p.ctx
+	}
+	return &guardIterator{iterator: openStage(ctx, p.tail)}
+}
+
+func (p *pipeline) context() context.Context {
+	if p.ctx == nil {
+		return context.Background()
+	}
+	return p.ctx
+}
+
+// evaluate pulls all the elements of the pipeline, the error occurred is recorded so that Err can report it.
+func (p *pipeline) evaluate() ([]*element, error) {
+	elements, err := drain(p.iterator())
+	p.record(err)
+	return elements, err
+}
+
+func (p *pipeline) record(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lastErr = err
+}
+
+// Err returns the error occurred in the latest evaluation of the pipeline, or the error of its context if the context
+// is done.
+func (p *pipeline) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.lastErr != nil {
+		return p.lastErr
+	}
+	return ctxErr(p.ctx)
+}
+
+// ctxErr returns the error of ctx if it's done, nil is returned if ctx is nil or not done.
+func ctxErr(ctx context.Context) error {
+	if ctx == nil {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		return nil
+	}
+}
+
+// doneOf returns the done channel of ctx, a nil channel is returned if ctx is nil, which is never ready.
+func doneOf(ctx context.Context) <-chan struct{} {
+	if ctx == nil {
+		return nil
+	}
+	return ctx.Done()
+}
+
+// drain pulls all the remaining elements of it, the elements pulled are returned along with the error occurred.
+func drain(it iterator) ([]*element, error) {
+	elements := make([]*element, 0)
+	for e, ok := it.next(); ok; e, ok = it.next() {
+		elements = append(elements, e)
+	}
+	return elements, it.err()
+}
+
+// iteratorWithin returns an iterator pulling the elements of s, which is a source of a stream evaluated with ctx.
+func iteratorWithin(ctx context.Context, s Stream) iterator {
+	switch s := s.(type) {
+	case *sequentialStream:
+		return s.within(ctx)
+	case *parallelStream:
+		return s.within(ctx)
+	}
+	return iteratorOf(s)
+}
+
+// iteratorOf returns an iterator pulling the elements of s.
+func iteratorOf(s Stream) iterator {
+	switch s := s.(type) {
+	case *sequentialStream:
+		return s.iterator()
+	case *parallelStream:
+		return s.iterator()
+	case *errStream:
+		return &errIterator{s.err}
+	}
+	var data []interface{}
+	if err := s.Collect(&data); err != nil {
+		return &errIterator{err}
+	}
+	elements, _ := convertDataToElements(data)
+	return &sliceIterator{elements: elements}
+}
+
+func sliceStage(elements []*element) stage {
+	if len(elements) <= 0 {
+		return nil
+	}
+	return func(ctx context.Context) iterator {
+		return &sliceIterator{ctx: ctx, elements: elements}
+	}
+}
+
+func filterStage(upstream stage, predicate func(val interface{}) (match bool)) stage {
+	return func(ctx context.Context) iterator {
+		return &filterIterator{iterator: openStage(ctx, upstream), predicate: predicate}
+	}
+}
+
+func filterEStage(upstream stage, mode ErrorMode, predicate func(val interface{}) (match bool, err error)) stage {
+	return func(ctx context.Context) iterator {
+		return &filterEIterator{iterator: openStage(ctx, upstream), predicate: predicate, sink: errorSink{mode: mode}}
+	}
+}
+
+func mapEStage(upstream stage, mode ErrorMode, mapper func(src interface{}) (dest interface{}, err error)) stage {
+	return func(ctx context.Context) iterator {
+		return &mapEIterator{iterator: openStage(ctx, upstream), mapper: mapper, sink: errorSink{mode: mode}}
+	}
+}
+
+func mapStage(upstream stage, op string, mapper func(src interface{}) (dest interface{})) stage {
+	return func(ctx context.Context) iterator {
+		return &mapIterator{iterator: openStage(ctx, upstream), op: op, mapper: mapper}
+	}
+}
+
+func peekStage(upstream stage, action func(val interface{})) stage {
+	return func(ctx context.Context) iterator {
+		return &peekIterator{iterator: openStage(ctx, upstream), action: action}
+	}
+}
+
+func limitStage(upstream stage, maxSize int) stage {
+	return func(ctx context.Context) iterator {
+		return &limitIterator{iterator: openStage(ctx, upstream), remain: maxSize}
+	}
+}
+
+func skipStage(upstream stage, n int) stage {
+	return func(ctx context.Context) iterator {
+		return &skipIterator{iterator: openStage(ctx, upstream), n: n}
+	}
+}
+
+func whileStage(upstream stage, op string, parallelism int, take bool, predicate func(val interface{}) (match bool)) stage {
+	return func(ctx context.Context) iterator {
+		return &whileIterator{
+			iterator:    openStage(ctx, upstream),
+			ctx:         ctx,
+			op:          op,
+			predicate:   predicate,
+			parallelism: parallelism,
+			take:        take,
+		}
+	}
+}
+
+func distinctStage(upstream stage, hashcode func(obj interface{}) interface{}, equals func(a, b interface{}) bool) stage {
+	return func(ctx context.Context) iterator {
+		return &distinctIterator{
+			iterator: openStage(ctx, upstream),
+			hashcode: hashcode,
+			equals:   equals,
+			seen:     make(map[interface{}][]interface{}),
+		}
+	}
+}
+
+func flatMapStage(upstream stage, mapper func(val interface{}) Stream) stage {
+	return flatMapEStage(upstream, "FlatMap", StopOnFirstError, func(val interface{}) (Stream, error) {
+		return mapper(val), nil
+	})
+}
+
+func flatMapEStage(upstream stage, op string, mode ErrorMode, mapper func(val interface{}) (Stream, error)) stage {
+	return func(ctx context.Context) iterator {
+		return &flatMapIterator{upstream: openStage(ctx, upstream), op: op, mapper: mapper, sink: errorSink{mode: mode}}
+	}
+}
+
+func concatStage(streams ...Stream) stage {
+	return func(ctx context.Context) iterator {
+		iterators := make([]iterator, 0, len(streams))
+		for _, s := range streams {
+			iterators = append(iterators, iteratorWithin(ctx, s))
+		}
+		return &concatIterator{ctx: ctx, iterators: iterators}
+	}
+}
+
+func windowStage(upstream stage, size, step int, partial bool) stage {
+	return func(ctx context.Context) iterator {
+		return &windowIterator{iterator: openStage(ctx, upstream), size: size, step: step, partial: partial}
+	}
+}
+
+func zipStage(a, b Stream, combiner func(first, second interface{}) interface{}) stage {
+	return func(ctx context.Context) iterator {
+		return &zipIterator{ctx: ctx, first: iteratorWithin(ctx, a), second: iteratorWithin(ctx, b), combiner: combiner}
+	}
+}
+
+// sharedStage evaluates s once it's opened for the first time, all the iterators opened from it share the elements.
+func sharedStage(s Stream) stage {
+	var once sync.Once
+	var elements []*element
+	var err error
+	return func(ctx context.Context) iterator {
+		once.Do(func() {
+			elements, err = drain(iteratorOf(s))
+		})
+		if err != nil {
+			return &errIterator{err}
+		}
+		return &sliceIterator{ctx: ctx, elements: elements}
+	}
+}
+
+func barrierStage(upstream stage, compute func(ctx context.Context, elements []*element) ([]*element, error)) stage {
+	return func(ctx context.Context) iterator {
+		return &barrierIterator{upstream: openStage(ctx, upstream), ctx: ctx, compute: compute}
+	}
+}
+
+func batchStage(upstream stage, parallelism int,
+	compute func(ctx context.Context, offset int, batch []*element) ([]*element, error)) stage {
+	return func(ctx context.Context) iterator {
+		return &batchIterator{upstream: openStage(ctx, upstream), ctx: ctx, compute: compute,
+			size: firstBatchSize(parallelism)}
+	}
+}
+
+func sortedStage(upstream stage, less func(a, b interface{}) bool) stage {
+	return barrierStage(upstream, func(_ context.Context, elements []*element) ([]*element, error) {
+		err := guard("Sorted", -1, func() {
+			sort.SliceStable(elements, func(i, j int) bool {
+				return less(elements[i].data, elements[j].data)
+			})
+		})
+		return elements, err
+	})
+}
+
+func openStage(ctx context.Context, s stage) iterator {
+	if s == nil {
+		return emptyIterator{}
+	}
+	return s(ctx)
+}
+
+func (emptyIterator) next() (*element, bool) {
+	return nil, false
+}
+
+func (emptyIterator) err() error {
+	return nil
+}
+
+func (e *errIterator) next() (*element, bool) {
+	return nil, false
+}
+
+func (e *errIterator) err() error {
+	return e.e
+}
+
+func (s *sliceIterator) next() (*element, bool) {
+	if s.index >= len(s.elements) || s.e != nil {
+		return nil, false
+	}
+	if s.e = ctxErr(s.ctx); s.e != nil {
+		return nil, false
+	}
+	e := s.elements[s.index]
+	s.index++
+	return e, true
+}
+
+func (s *sliceIterator) err() error {
+	return s.e
+}
+
+func (f *filterIterator) next() (*element, bool) {
+	for e, ok := f.iterator.next(); ok; e, ok = f.iterator.next() {
+		if f.test(e.data) {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+func (f *filterIterator) test(data interface{}) bool {
+	defer catch("Filter", f.index)
+	f.index++
+	return f.predicate(data)
+}
+
+func (m *mapIterator) next() (*element, bool) {
+	e, ok := m.iterator.next()
+	if !ok {
+		return nil, false
+	}
+	defer catch(m.op, m.index)
+	m.index++
+	return newElement(m.mapper(e.data)), true
+}
+
+func (f *filterEIterator) next() (*element, bool) {
+	for !f.stopped {
+		e, ok := f.iterator.next()
+		if !ok {
+			break
+		}
+		match, err := f.test(e.data)
+		if err != nil {
+			f.stopped = f.sink.add(err)
+		} else if match {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+func (f *filterEIterator) test(data interface{}) (bool, error) {
+	defer catch("FilterE", f.index)
+	f.index++
+	return f.predicate(data)
+}
+
+func (f *filterEIterator) err() error {
+	return f.sink.result(f.iterator.err())
+}
+
+func (m *mapEIterator) next() (*element, bool) {
+	for !m.stopped {
+		e, ok := m.iterator.next()
+		if !ok {
+			break
+		}
+		dest, err := m.apply(e.data)
+		if err == nil {
+			return newElement(dest), true
+		}
+		m.stopped = m.sink.add(err)
+	}
+	return nil, false
+}
+
+func (m *mapEIterator) apply(data interface{}) (interface{}, error) {
+	defer catch("MapE", m.index)
+	m.index++
+	return m.mapper(data)
+}
+
+func (m *mapEIterator) err() error {
+	return m.sink.result(m.iterator.err())
+}
+
+func (p *peekIterator) next() (*element, bool) {
+	e, ok := p.iterator.next()
+	if !ok {
+		return nil, false
+	}
+	defer catch("Peek", p.index)
+	p.index++
+	p.action(e.data)
+	return e, true
+}
+
+func (l *limitIterator) next() (*element, bool) {
+	// stop pulling the upstream as soon as enough elements were produced
+	if l.remain <= 0 {
+		return nil, false
+	}
+	e, ok := l.iterator.next()
+	if ok {
+		l.remain--
+	}
+	return e, ok
+}
+
+func (s *skipIterator) next() (*element, bool) {
+	for ; s.n > 0; s.n-- {
+		if _, ok := s.iterator.next(); !ok {
+			return nil, false
+		}
+	}
+	return s.iterator.next()
+}
+
+func (w *whileIterator) next() (*element, bool) {
+	for len(w.pending) == 0 {
+		if w.e != nil {
+			return nil, false
+		}
+		if w.mismatched {
+			if w.take {
+				return nil, false
+			}
+			return w.iterator.next()
+		}
+		if !w.pull() {
+			return nil, false
+		}
+	}
+	e := w.pending[0]
+	w.pending = w.pending[1:]
+	return e, true
+}
+
+// pull pulls and tests a batch of the upstream elements, false is returned if the upstream has no more element or the
+// test failed.
+func (w *whileIterator) pull() bool {
+	size := batchSize(w.parallelism)
+	batch := make([]*element, 0, size)
+	for len(batch) < size {
+		e, ok := w.iterator.next()
+		if !ok {
+			break
+		}
+		batch = append(batch, e)
+	}
+	if len(batch) == 0 {
+		return false
+	}
+	n, err := matchingPrefix(w.ctx, w.op, w.parallelism, w.index, batch, func(e *element) bool {
+		return w.predicate(e.data)
+	})
+	if err != nil {
+		w.e = err
+		return false
+	}
+	w.index += len(batch)
+	w.mismatched = n < len(batch)
+	if w.take {
+		w.pending = batch[:n]
+	} else {
+		w.pending = batch[n:]
+	}
+	return true
+}
+
+func (w *whileIterator) err() error {
+	if w.e != nil {
+		return w.e
+	}
+	return w.iterator.err()
+}
+
+func (d *distinctIterator) next() (*element, bool) {
+	for e, ok := d.iterator.next(); ok; e, ok = d.iterator.next() {
+		if d.firstSeen(e.data) {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+// firstSeen reports whether data is not equal to any element seen before, and marks it seen.
+func (d *distinctIterator) firstSeen(data interface{}) bool {
+	defer catch("Distinct", d.index)
+	d.index++
+	code := d.hashcode(data)
+	seens := d.seen[code]
+	for _, seen := range seens {
+		if d.equals(data, seen) {
+			return false
+		}
+	}
+	d.seen[code] = append(seens, data)
+	return true
+}
+
+func (f *flatMapIterator) next() (*element, bool) {
+	for f.e == nil {
+		if f.current != nil {
+			if e, ok := f.current.next(); ok {
+				return e, true
+			}
+			if f.e = f.current.err(); f.e != nil {
+				break
+			}
+		}
+		e, ok := f.upstream.next()
+		if !ok {
+			break
+		}
+		s, err := f.apply(e.data)
+		if err != nil {
+			f.current = nil
+			if f.sink.add(err) {
+				f.e = err
+			}
+			continue
+		}
+		f.current = iteratorOf(s)
+	}
+	return nil, false
+}
+
+func (f *flatMapIterator) apply(data interface{}) (Stream, error) {
+	defer catch(f.op, f.index)
+	f.index++
+	return f.mapper(data)
+}
+
+func (f *flatMapIterator) err() error {
+	if f.e != nil {
+		if upstream := f.sink.result(f.upstream.err()); isCollected(upstream) {
+			return mergeErrors(upstream, f.e)
+		}
+		return f.e
+	}
+	return f.sink.result(f.upstream.err())
+}
+
+func (g *guardIterator) next() (e *element, ok bool) {
+	if g.e != nil {
+		return nil, false
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			g.e = newPanicError("", -1, r)
+			e, ok = nil, false
+		}
+	}()
+	return g.iterator.next()
+}
+
+func (g *guardIterator) err() error {
+	if g.e != nil {
+		if upstream := g.iterator.err(); isCollected(upstream) {
+			return mergeErrors(upstream, g.e)
+		}
+		return g.e
+	}
+	return g.iterator.err()
+}
+
+func (c *concatIterator) next() (*element, bool) {
+	for c.e == nil && len(c.iterators) > 0 {
+		// a stream with its own context isn't stopped by the context of the concatenation
+		if c.e = ctxErr(c.ctx); c.e != nil {
+			break
+		}
+		it := c.iterators[0]
+		if e, ok := it.next(); ok {
+			return e, true
+		}
+		if c.e = it.err(); c.e == nil {
+			c.iterators = c.iterators[1:]
+		}
+	}
+	return nil, false
+}
+
+func (c *concatIterator) err() error {
+	return c.e
+}
+
+func (w *windowIterator) next() (*element, bool) {
+	if w.done {
+		return nil, false
+	}
+	if !w.started {
+		w.started = true
+	} else if w.step < w.size {
+		w.window = w.window[w.step:]
+	} else {
+		w.window = w.window[:0]
+		for i := w.size; i < w.step; i++ {
+			if _, ok := w.iterator.next(); !ok {
+				w.done = true
+				return nil, false
+			}
+		}
+	}
+	for len(w.window) < w.size {
+		e, ok := w.iterator.next()
+		if !ok {
+			w.done = true
+			if w.partial && len(w.window) > 0 {
+				break
+			}
+			return nil, false
+		}
+		w.window = append(w.window, e.data)
+	}
+	window := make([]interface{}, len(w.window))
+	copy(window, w.window)
+	return newElement(window), true
+}
+
+func (z *zipIterator) next() (*element, bool) {
+	if z.done {
+		return nil, false
+	}
+	// a stream with its own context isn't stopped by the context of the zip
+	if z.e = ctxErr(z.ctx); z.e != nil {
+		z.done = true
+		return nil, false
+	}
+	first, ok := z.first.next()
+	if !ok {
+		z.done = true
+		return nil, false
+	}
+	second, ok := z.second.next()
+	if !ok {
+		z.done = true
+		return nil, false
+	}
+	defer catch("Zip", z.index)
+	z.index++
+	return newElement(z.combiner(first.data, second.data)), true
+}
+
+func (z *zipIterator) err() error {
+	if z.e != nil {
+		return z.e
+	}
+	if err := z.first.err(); err != nil {
+		return err
+	}
+	return z.second.err()
+}
+
+func (b *barrierIterator) next() (*element, bool) {
+	if !b.done {
+		b.done = true
+		elements, err := drain(b.upstream)
+		if err == nil || isCollected(err) {
+			// the errors collected by the upstream stages don't stop the stream, go on with the remaining elements
+			var computeErr error
+			elements, computeErr = b.compute(b.ctx, elements)
+			err = mergeErrors(err, computeErr)
+		}
+		b.e = err
+		if err != nil && !isCollected(err) {
+			return nil, false
+		}
+		b.elements = elements
+	}
+	return b.sliceIterator.next()
+}
+
+func (b *barrierIterator) err() error {
+	return mergeErrors(b.e, b.sliceIterator.e)
+}
+
+func (b *batchIterator) next() (*element, bool) {
+	for len(b.pending) == 0 {
+		if b.done || (b.e != nil && !isCollected(b.e)) {
+			return nil, false
+		}
+		batch := pullBatch(b.upstream, b.size)
+		if len(batch) < b.size {
+			b.done = true
+			if err := b.upstream.err(); err != nil && !isCollected(err) {
+				return nil, false
+			}
+		}
+		if len(batch) > 0 {
+			elements, err := b.compute(b.ctx, b.offset, batch)
+			// the errors collected don't stop the stream, go on with the remaining elements
+			b.e = mergeErrors(b.e, err)
+			b.pending = elements
+		}
+		b.offset += len(batch)
+		b.size = nextBatchSize(b.size)
+	}
+	e := b.pending[0]
+	b.pending = b.pending[1:]
+	return e, true
+}
+
+func (b *batchIterator) err() error {
+	upstream := b.upstream.err()
+	if upstream != nil && !isCollected(upstream) {
+		// the upstream failed after the errors of the batches computed were collected
+		return mergeErrors(b.e, upstream)
+	}
+	return mergeErrors(upstream, b.e)
+}
@@ -0,0 +1,170 @@
+package gostream
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPipelineLazyEvaluation(t *testing.T) {
+	for _, newStream := range []func([]*element) Stream{newSequentialStreamForTest, newParallelStreamForTest} {
+		var called int32
+		s := newStream(intSliceToElements([]int{1, 2, 3})).Filter(func(val interface{}) (match bool) {
+			atomic.AddInt32(&called, 1)
+			return true
+		}).Map(func(src interface{}) (dest interface{}) {
+			atomic.AddInt32(&called, 1)
+			return src
+		})
+		assert.Equal(t, int32(0), atomic.LoadInt32(&called), "no element should be evaluated before a terminal operation")
+		var dest []int
+		assert.NoError(t, s.Collect(&dest))
+		assert.Equal(t, int32(6), atomic.LoadInt32(&called))
+		assertSliceEquals(t, []int{1, 2, 3}, dest)
+	}
+}
+
+func TestPipelineShortCircuit(t *testing.T) {
+	t.Run("test limit", func(t *testing.T) {
+		called := 0
+		var dest []int
+		err := NewSequentialStream([]int{1, 2, 3, 4, 5, 6}).Map(func(src interface{}) (dest interface{}) {
+			called++
+			return src.(int) * 2
+		}).Limit(2).Collect(&dest)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, called)
+		assertSliceEquals(t, []int{2, 4}, dest)
+	})
+	t.Run("test first or default", func(t *testing.T) {
+		called := 0
+		var dest int
+		err := NewSequentialStream([]int{1, 2, 3, 4, 5, 6}).Filter(func(val interface{}) (match bool) {
+			called++
+			return val.(int) > 1
+		}).FirstOrDefault(&dest)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, called)
+		assert.Equal(t, 2, dest)
+	})
+}
+
+func TestPipelineErr(t *testing.T) {
+	s := NewSequentialStream([]int{1, 2, 3}).FlatMap(func(val interface{}) Stream {
+		return &errStream{err: errors.New("")}
+	})
+	assert.NoError(t, s.Err())
+	var dest []int
+	assert.Error(t, s.Collect(&dest))
+	assert.Error(t, s.Err())
+}
+
+func TestPipelineReusable(t *testing.T) {
+	s := NewSequentialStream([]int{3, 1, 2}).Sorted(func(a, b interface{}) bool {
+		return a.(int) < b.(int)
+	})
+	for i := 0; i < 2; i++ {
+		var dest []int
+		assert.NoError(t, s.Collect(&dest))
+		assertSliceEquals(t, []int{1, 2, 3}, dest)
+	}
+}
+
+func TestStreamWithContext(t *testing.T) {
+	t.Run("test cancelled before evaluation", func(t *testing.T) {
+		for _, newStream := range []func([]*element) Stream{newSequentialStreamForTest, newParallelStreamForTest} {
+			ctx, cancel := context.WithCancel(context.Background())
+			s := newStream(intSliceToElements([]int{1, 2, 3})).WithContext(ctx).Map(func(src interface{}) (dest interface{}) {
+				return src
+			})
+			assert.NoError(t, s.Err())
+			cancel()
+			var dest []int
+			assert.Equal(t, context.Canceled, s.Collect(&dest))
+			assert.Equal(t, context.Canceled, s.Err())
+		}
+	})
+	t.Run("test cancelled while evaluating", func(t *testing.T) {
+		for _, newStream := range []func([]*element) Stream{newSequentialStreamForTest, newParallelStreamForTest} {
+			ctx, cancel := context.WithCancel(context.Background())
+			var called int32
+			s := newStream(intSliceToElements(make([]int, 1000))).WithContext(ctx).Map(func(src interface{}) (dest interface{}) {
+				if atomic.AddInt32(&called, 1) == 1 {
+					cancel()
+				}
+				return src
+			})
+			var dest []int
+			assert.Equal(t, context.Canceled, s.Collect(&dest))
+			if !s.IsParallel() {
+				assert.Equal(t, int32(1), atomic.LoadInt32(&called))
+			}
+		}
+	})
+	t.Run("test blocking channel", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		var dest []int
+		err := NewStreamFromChan(make(chan int)).WithContext(ctx).Collect(&dest)
+		assert.Equal(t, context.DeadlineExceeded, err)
+	})
+	t.Run("test infinite zip and concat", func(t *testing.T) {
+		one := func() interface{} {
+			return 1
+		}
+		sum := func(first, second interface{}) interface{} {
+			return first.(int) + second.(int)
+		}
+		for _, s := range []Stream{
+			Zip(Generate(one), Generate(one), sum),
+			ConcatStream(Generate(one), Generate(one)),
+			Zip(NewStreamFromChan(make(chan int)), Generate(one), sum),
+			ConcatStream(NewSequentialStream([]int{1}), NewStreamFromChan(make(chan int))),
+		} {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			err := s.WithContext(ctx).ForEach(func(val interface{}) {})
+			assert.Equal(t, context.DeadlineExceeded, err)
+			cancel()
+		}
+	})
+	t.Run("test abandoned channel sink", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		s := NewSequentialStream([]int{1, 2, 3}).WithContext(ctx)
+		ch := s.Chan()
+		assert.Equal(t, 1, <-ch)
+		cancel()
+		for range ch {
+		}
+		assert.Equal(t, context.Canceled, s.Err())
+	})
+	t.Run("test error stream", func(t *testing.T) {
+		assert.Same(t, testErrStream, testErrStream.WithContext(context.Background()))
+	})
+}
+
+func TestStreamWithParallelism(t *testing.T) {
+	var running, maxRunning int32
+	s := NewSequentialStream(make([]int, 100)).Parallel().WithParallelism(2).Map(func(src interface{}) (dest interface{}) {
+		r := atomic.AddInt32(&running, 1)
+		for m := atomic.LoadInt32(&maxRunning); r > m && !atomic.CompareAndSwapInt32(&maxRunning, m, r); {
+			m = atomic.LoadInt32(&maxRunning)
+		}
+		time.Sleep(time.Microsecond)
+		atomic.AddInt32(&running, -1)
+		return src
+	})
+	assert.True(t, s.IsParallel())
+	var res []int
+	assert.NoError(t, s.Collect(&res))
+	assert.Len(t, res, 100)
+	assert.True(t, maxRunning <= 2)
+
+	for _, s := range []Stream{NewSequentialStream([]int{1}), NewParallelStream([]int{1})} {
+		assert.Error(t, s.WithParallelism(0).Err())
+		assert.NoError(t, s.WithParallelism(1).Err())
+	}
+	assert.Same(t, testErrStream, testErrStream.WithParallelism(2))
+}
@@ -0,0 +1,166 @@
+package gostream
+
+// MinBy returns the element of s whose key is the minimum, or nil if s is empty. The first one in encounter order is
+// returned if several elements have the minimum key, and NaN is less than any other key.
+func MinBy[K Ordered](s Stream, key func(val interface{}) K) (interface{}, error) {
+	return s.Min(Comparing(key).Less)
+}
+
+// MaxBy returns the element of s whose key is the maximum, or nil if s is empty. The first one in encounter order is
+// returned if several elements have the maximum key, and NaN is less than any other key.
+func MaxBy[K Ordered](s Stream, key func(val interface{}) K) (interface{}, error) {
+	return s.Max(Comparing(key).Less)
+}
+
+// Count is O(1) if the pipeline consists of the slice source only, otherwise the elements are pulled and counted.
+func (p *pipeline) Count() (int, error) {
+	if p.tail == nil || p.source != nil {
+		if err := ctxErr(p.ctx); err != nil {
+			return 0, err
+		}
+		return len(p.source), nil
+	}
+	it := p.iterator()
+	count := 0
+	for _, ok := it.next(); ok; _, ok = it.next() {
+		count++
+	}
+	if err := it.err(); err != nil {
+		p.record(err)
+		return 0, err
+	}
+	return count, nil
+}
+
+func (s *sequentialStream) Min(less func(a, b interface{}) bool) (interface{}, error) {
+	return s.reduce("Min", minOf(less))
+}
+
+func (s *sequentialStream) Max(less func(a, b interface{}) bool) (interface{}, error) {
+	return s.reduce("Max", maxOf(less))
+}
+
+func (p *parallelStream) Min(less func(a, b interface{}) bool) (interface{}, error) {
+	return p.reduce("Min", minOf(less))
+}
+
+func (p *parallelStream) Max(less func(a, b interface{}) bool) (interface{}, error) {
+	return p.reduce("Max", maxOf(less))
+}
+
+func (s *sequentialStream) ReduceWithIdentity(identity interface{}, accumulator func(a, b interface{}) (c interface{})) (interface{}, error) {
+	return s.fold("ReduceWithIdentity", identity, accumulator)
+}
+
+func (s *sequentialStream) ReduceTo(identity interface{}, accumulator func(acc, val interface{}) interface{},
+	_ func(a, b interface{}) interface{}) (interface{}, error) {
+	return s.fold("ReduceTo", identity, accumulator)
+}
+
+// fold folds the elements into identity with accumulator one at a time, op is the name of the operation reported by
+// the *PanicError.
+func (s *sequentialStream) fold(op string, identity interface{}, accumulator func(acc, val interface{}) interface{}) (interface{}, error) {
+	it := s.iterator()
+	result := identity
+	index := 0
+	err := guardAt(op, &index, func() {
+		for e, ok := it.next(); ok; e, ok = it.next() {
+			result = accumulator(result, e.data)
+			index++
+		}
+	})
+	if err == nil {
+		err = it.err()
+	}
+	if err == nil {
+		// an empty stream has no stage reporting the error of the context
+		err = ctxErr(s.ctx)
+	}
+	if err != nil {
+		s.record(err)
+		return nil, err
+	}
+	return result, nil
+}
+
+func (p *parallelStream) ReduceWithIdentity(identity interface{}, accumulator func(a, b interface{}) (c interface{})) (interface{}, error) {
+	return p.fold("ReduceWithIdentity", identity, accumulator, accumulator)
+}
+
+func (p *parallelStream) ReduceTo(identity interface{}, accumulator func(acc, val interface{}) interface{},
+	combiner func(a, b interface{}) interface{}) (interface{}, error) {
+	return p.fold("ReduceTo", identity, accumulator, combiner)
+}
+
+// fold folds the partitions of the elements into identity with accumulator by the fork/join pool, and merges the
+// partial results with combiner in encounter order. op is the name of the operation reported by the *PanicError.
+func (p *parallelStream) fold(op string, identity interface{}, accumulator func(acc, val interface{}) interface{},
+	combiner func(a, b interface{}) interface{}) (interface{}, error) {
+	elements, err := p.evaluate()
+	if err != nil {
+		return nil, err
+	}
+	if err := ctxErr(p.context()); err != nil {
+		// an empty stream has no stage reporting the error of the context
+		p.record(err)
+		return nil, err
+	}
+	if len(elements) == 0 {
+		return identity, nil
+	}
+	var result interface{}
+	err = guard(op, -1, func() {
+		result = DefaultForkJoinPool().Invoke(newFoldRecursiveTask(op, identity, accumulator, combiner, elements, 0,
+			len(elements)-1))
+	})
+	if err == nil {
+		err = ctxErr(p.ctx)
+	}
+	if err != nil {
+		p.record(err)
+		return nil, err
+	}
+	return result, nil
+}
+
+func (e *errStream) Count() (int, error) {
+	return 0, e.err
+}
+
+func (e *errStream) Min(func(a, b interface{}) bool) (interface{}, error) {
+	return nil, e.err
+}
+
+func (e *errStream) Max(func(a, b interface{}) bool) (interface{}, error) {
+	return nil, e.err
+}
+
+func (e *errStream) ReduceWithIdentity(interface{}, func(a, b interface{}) (c interface{})) (interface{}, error) {
+	return nil, e.err
+}
+
+func (e *errStream) ReduceTo(interface{}, func(acc, val interface{}) interface{}, func(a, b interface{}) interface{}) (interface{}, error) {
+	return nil, e.err
+}
+
+// minOf returns an accumulator keeping the lesser one of a and b, a is kept if they are equal, so the reduction finds
+// the first minimum in encounter order.
+func minOf(less func(a, b interface{}) bool) func(a, b interface{}) interface{} {
+	return func(a, b interface{}) interface{} {
+		if less(b, a) {
+			return b
+		}
+		return a
+	}
+}
+
+// maxOf returns an accumulator keeping the greater one of a and b, a is kept if they are equal, so the reduction
+// finds the first maximum in encounter order.
+func maxOf(less func(a, b interface{}) bool) func(a, b interface{}) interface{} {
+	return func(a, b interface{}) interface{} {
+		if less(a, b) {
+			return b
+		}
+		return a
+	}
+}
@@ -0,0 +1,225 @@
+package gostream
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestStreamCount(t *testing.T) {
+	for _, newStream := range []func(data interface{}) Stream{NewSequentialStream, NewParallelStream} {
+		t.Run("test slice source", func(t *testing.T) {
+			called := 0
+			s := newStream([]int{1, 2, 3}).WithParallelism(2).Peek(func(val interface{}) {
+				called++
+			})
+			count, err := newStream([]int{1, 2, 3}).WithParallelism(2).Sequential().Count()
+			assert.NoError(t, err)
+			assert.Equal(t, 3, count)
+			assert.Equal(t, 0, called)
+
+			count, err = s.Filter(func(val interface{}) (match bool) {
+				return val.(int) > 1
+			}).Count()
+			assert.NoError(t, err)
+			assert.Equal(t, 2, count)
+			assert.Equal(t, 3, called)
+		})
+
+		t.Run("test empty", func(t *testing.T) {
+			count, err := newStream([]int{}).Count()
+			assert.NoError(t, err)
+			assert.Equal(t, 0, count)
+		})
+
+		t.Run("test cancelled", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err := newStream([]int{1}).WithContext(ctx).Count()
+			assert.Equal(t, context.Canceled, err)
+			_, err = newStream([]int{1}).Map(func(src interface{}) (dest interface{}) {
+				return src
+			}).WithContext(ctx).Count()
+			assert.Equal(t, context.Canceled, err)
+		})
+	}
+
+	count, err := Iterate(1, func(val interface{}) bool {
+		return val.(int) <= 5
+	}, func(prev interface{}) interface{} {
+		return prev.(int) + 1
+	}).Count()
+	assert.NoError(t, err)
+	assert.Equal(t, 5, count)
+
+	_, err = testErrStream.Count()
+	assert.Error(t, err)
+}
+
+func TestStreamMinMax(t *testing.T) {
+	type item struct {
+		name  string
+		price int
+	}
+	items := []item{{"b", 3}, {"a", 1}, {"c", 3}, {"d", 1}, {"e", 2}}
+	ints := make([]int, 1000)
+	for i := range ints {
+		ints[i] = (i * 7919) % 1000
+	}
+	less := func(a, b interface{}) bool {
+		return a.(item).price < b.(item).price
+	}
+	for _, newStream := range []func(data interface{}) Stream{NewSequentialStream, NewParallelStream} {
+		min, err := newStream(items).Min(less)
+		assert.NoError(t, err)
+		assert.Equal(t, item{"a", 1}, min)
+		max, err := newStream(items).Max(less)
+		assert.NoError(t, err)
+		assert.Equal(t, item{"b", 3}, max)
+
+		min, err = MinBy(newStream(items), func(val interface{}) string {
+			return val.(item).name
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, item{"a", 1}, min)
+		max, err = MaxBy(newStream(items), func(val interface{}) int {
+			return val.(item).price
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, item{"b", 3}, max)
+
+		min, err = MinBy(newStream(ints), func(val interface{}) int {
+			return val.(int)
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, min)
+		max, err = MaxBy(newStream(ints), func(val interface{}) int {
+			return val.(int)
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 999, max)
+
+		min, err = newStream([]item{}).Min(less)
+		assert.NoError(t, err)
+		assert.Nil(t, min)
+
+		_, err = newStream(items).Max(func(a, b interface{}) bool {
+			panic("boom")
+		})
+		var p *PanicError
+		if assert.True(t, errors.As(err, &p)) {
+			assert.Equal(t, "Max", p.Op)
+		}
+	}
+
+	_, err := testErrStream.Min(nil)
+	assert.Error(t, err)
+	_, err = testErrStream.Max(nil)
+	assert.Error(t, err)
+}
+
+func TestStreamReduceTo(t *testing.T) {
+	type widget struct {
+		color  string
+		weight int
+	}
+	widgets := make([]widget, 1000)
+	for i := range widgets {
+		widgets[i] = widget{[]string{"red", "green"}[i%2], i}
+	}
+	for _, newStream := range []func(data interface{}) Stream{NewSequentialStream, NewParallelStream} {
+		total, err := newStream(widgets).ReduceTo(0, func(acc, val interface{}) interface{} {
+			return acc.(int) + val.(widget).weight
+		}, func(a, b interface{}) interface{} {
+			return a.(int) + b.(int)
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 999*1000/2, total)
+
+		colors, err := newStream(widgets).Limit(5).ReduceTo("", func(acc, val interface{}) interface{} {
+			return acc.(string) + val.(widget).color[:1]
+		}, func(a, b interface{}) interface{} {
+			return a.(string) + b.(string)
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "rgrgr", colors)
+
+		sum, err := newStream(widgets).Map(func(src interface{}) (dest interface{}) {
+			return src.(widget).weight
+		}).ReduceWithIdentity(0, func(a, b interface{}) (c interface{}) {
+			return a.(int) + b.(int)
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 999*1000/2, sum)
+
+		total, err = newStream([]widget{}).ReduceTo(0, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, total)
+		sum, err = newStream([]int{}).ReduceWithIdentity(1, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, sum)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = newStream(widgets).WithContext(ctx).ReduceWithIdentity(0, func(a, b interface{}) (c interface{}) {
+			return a
+		})
+		assert.Equal(t, context.Canceled, err)
+		empty := newStream([]int{}).WithContext(ctx)
+		_, err = empty.ReduceWithIdentity(0, nil)
+		assert.Equal(t, context.Canceled, err)
+		_, err = empty.ReduceTo(0, nil, nil)
+		assert.Equal(t, context.Canceled, err)
+	}
+
+	_, err := testErrStream.ReduceWithIdentity(0, nil)
+	assert.Error(t, err)
+	_, err = testErrStream.ReduceTo(0, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestNumberStreamReduceWithIdentity(t *testing.T) {
+	ints := make([]int, 1000)
+	for i := range ints {
+		ints[i] = i
+	}
+	add := func(a, b int) (c int) {
+		return a + b
+	}
+	for _, newStream := range []func([]int) IntStream{NewSequentialIntStream, NewParallelIntStream} {
+		sum, err := newStream(ints).ReduceWithIdentity(0, add)
+		assert.NoError(t, err)
+		assert.Equal(t, 999*1000/2, sum)
+
+		sum, err = newStream(ints).Filter(func(val int) (match bool) {
+			return val < 0
+		}).ReduceWithIdentity(0, add)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, sum)
+
+		product, err := newStream(nil).ReduceWithIdentity(1, func(a, b int) (c int) {
+			return a * b
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, product)
+	}
+
+	sum, err := IntRange(0, 1000).Parallel().ReduceWithIdentity(0, add)
+	assert.NoError(t, err)
+	assert.Equal(t, 999*1000/2, sum)
+
+	for _, newStream := range []func([]float64) Float64Stream{NewSequentialFloat64Stream, NewParallelFloat64Stream} {
+		max, err := newStream([]float64{}).ReduceWithIdentity(-1, func(a, b float64) (c float64) {
+			if a > b {
+				return a
+			}
+			return b
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, -1.0, max)
+	}
+
+	_, err = testErrIntStream.ReduceWithIdentity(0, add)
+	assert.Error(t, err)
+}
@@ -0,0 +1,208 @@
+package gostream
+
+import (
+	"fmt"
+	"math"
+)
+
+// NumberSummaryStatistics collects the count, sum, min, max, average and variance of numbers in a single pass.
+// The zero value is the statistics of no number, whose min, max, average and variance are 0.
+type NumberSummaryStatistics[N Number] struct {
+	count int
+	sum   summation[N]
+	min   N
+	max   N
+	// mean and m2 are maintained with Welford's algorithm, m2 is the sum of squares of differences from the mean.
+	mean float64
+	m2   float64
+}
+
+// IntSummaryStatistics is the NumberSummaryStatistics of int.
+type IntSummaryStatistics = NumberSummaryStatistics[int]
+
+// Float64SummaryStatistics is the NumberSummaryStatistics of float64.
+type Float64SummaryStatistics = NumberSummaryStatistics[float64]
+
+// Accept records val into the statistics.
+func (s *NumberSummaryStatistics[N]) Accept(val N) {
+	if s.count == 0 || val < s.min {
+		s.min = val
+	}
+	if s.count == 0 || val > s.max {
+		s.max = val
+	}
+	s.count++
+	s.sum.add(val)
+	x := float64(val)
+	delta := x - s.mean
+	s.mean += delta / float64(s.count)
+	s.m2 += delta * (x - s.mean)
+}
+
+// Combine merges other into the statistics, as if all the numbers recorded by other were accepted.
+func (s *NumberSummaryStatistics[N]) Combine(other *NumberSummaryStatistics[N]) {
+	if other.count == 0 {
+		return
+	}
+	if s.count == 0 {
+		*s = *other
+		return
+	}
+	if other.min < s.min {
+		s.min = other.min
+	}
+	if other.max > s.max {
+		s.max = other.max
+	}
+	count := s.count + other.count
+	delta := other.mean - s.mean
+	s.mean += delta * float64(other.count) / float64(count)
+	s.m2 += other.m2 + delta*delta*float64(s.count)*float64(other.count)/float64(count)
+	s.count = count
+	s.sum.merge(&other.sum)
+}
+
+// Count returns the number of numbers recorded.
+func (s *NumberSummaryStatistics[N]) Count() int {
+	return s.count
+}
+
+// Sum returns the sum of numbers recorded, which is the same as the Sum of a stream.
+func (s *NumberSummaryStatistics[N]) Sum() N {
+	return s.sum.value()
+}
+
+// Min returns the minimum number recorded.
+func (s *NumberSummaryStatistics[N]) Min() N {
+	return s.min
+}
+
+// Max returns the maximum number recorded.
+func (s *NumberSummaryStatistics[N]) Max() N {
+	return s.max
+}
+
+// Average returns the arithmetic mean of numbers recorded.
+func (s *NumberSummaryStatistics[N]) Average() float64 {
+	return s.mean
+}
+
+// Variance returns the population variance of numbers recorded.
+func (s *NumberSummaryStatistics[N]) Variance() float64 {
+	if s.count == 0 {
+		return 0
+	}
+	return s.m2 / float64(s.count)
+}
+
+// SampleVariance returns the sample variance of numbers recorded, which is 0 if fewer than 2 numbers were recorded.
+func (s *NumberSummaryStatistics[N]) SampleVariance() float64 {
+	if s.count < 2 {
+		return 0
+	}
+	return s.m2 / float64(s.count-1)
+}
+
+// StandardDeviation returns the population standard deviation of numbers recorded.
+func (s *NumberSummaryStatistics[N]) StandardDeviation() float64 {
+	return math.Sqrt(s.Variance())
+}
+
+func (s *NumberSummaryStatistics[N]) String() string {
+	return fmt.Sprintf("{count=%d, sum=%v, min=%v, average=%v, max=%v, stddev=%v}",
+		s.count, s.Sum(), s.min, s.mean, s.max, s.StandardDeviation())
+}
+
+func (s *sequentialNumberStream[N]) Median() (*float64, error) {
+	return s.Percentile(50)
+}
+
+func (s *sequentialNumberStream[N]) Percentile(p float64) (*float64, error) {
+	if err := checkPercentile(p); err != nil {
+		return nil, err
+	}
+	if err := ctxErr(s.ctx); err != nil {
+		return nil, err
+	}
+	return percentileOf(sortNumbers(s.elements), p), nil
+}
+
+func (s *sequentialNumberStream[N]) SummaryStatistics() (*NumberSummaryStatistics[N], error) {
+	if err := ctxErr(s.ctx); err != nil {
+		return nil, err
+	}
+	return summarize(s.elements), nil
+}
+
+func (p *parallelNumberStream[N]) Median() (*float64, error) {
+	return p.Percentile(50)
+}
+
+func (p *parallelNumberStream[N]) Percentile(percent float64) (*float64, error) {
+	if err := checkPercentile(percent); err != nil {
+		return nil, err
+	}
+	sorted, err := p.Sorted().Collect()
+	if err != nil {
+		return nil, err
+	}
+	return percentileOf(sorted, percent), nil
+}
+
+// SummaryStatistics of a parallel stream summarizes the chunks of the elements concurrently, and combines the partial
+// statistics.
+func (p *parallelNumberStream[N]) SummaryStatistics() (*NumberSummaryStatistics[N], error) {
+	chunks := numberChunks(p.elements, p.parallelism)
+	partials := make([]*NumberSummaryStatistics[N], len(chunks))
+	err := parallelEach(p.ctx, "SummaryStatistics", p.parallelism, len(chunks), func(i int) {
+		partials[i] = summarize(chunks[i])
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := &NumberSummaryStatistics[N]{}
+	for _, partial := range partials {
+		result.Combine(partial)
+	}
+	return result, nil
+}
+
+func (e *errNumberStream[N]) Median() (*float64, error) {
+	return nil, e.err
+}
+
+func (e *errNumberStream[N]) Percentile(float64) (*float64, error) {
+	return nil, e.err
+}
+
+func (e *errNumberStream[N]) SummaryStatistics() (*NumberSummaryStatistics[N], error) {
+	return nil, e.err
+}
+
+func summarize[N Number](numbers []N) *NumberSummaryStatistics[N] {
+	stats := &NumberSummaryStatistics[N]{}
+	for _, n := range numbers {
+		stats.Accept(n)
+	}
+	return stats
+}
+
+func checkPercentile(p float64) error {
+	if !(p >= 0 && p <= 100) {
+		return fmt.Errorf("percentile error, p is out of [0, 100]: %v", p)
+	}
+	return nil
+}
+
+// percentileOf returns the p-th percentile of sorted numbers by linear interpolation between the closest ranks, nil is
+// returned if there is no number.
+func percentileOf[N Number](sorted []N, p float64) *float64 {
+	if len(sorted) == 0 {
+		return nil
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	result := float64(sorted[lower]) + (float64(sorted[upper])-float64(sorted[lower]))*(rank-float64(lower))
+	return &result
+}
@@ -0,0 +1,123 @@
+package gostream
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestNumberStreamSummaryStatistics(t *testing.T) {
+	ints := make([]int, 1001)
+	for i := range ints {
+		ints[i] = i - 500
+	}
+	for _, s := range []IntStream{
+		NewSequentialIntStream(ints),
+		NewParallelIntStream(ints),
+		NewParallelIntStream(ints).WithParallelism(3),
+		IntRangeClosed(-500, 500),
+	} {
+		stats, err := s.SummaryStatistics()
+		assert.NoError(t, err)
+		assert.Equal(t, 1001, stats.Count())
+		assert.Equal(t, 0, stats.Sum())
+		assert.Equal(t, -500, stats.Min())
+		assert.Equal(t, 500, stats.Max())
+		assert.InDelta(t, 0, stats.Average(), 1e-9)
+		// the variance of the integers in [-n, n] is n(n+1)/3
+		assert.InDelta(t, 500.0*501/3, stats.Variance(), 1e-6)
+		assert.InDelta(t, 500.0*501*1001/3/1000, stats.SampleVariance(), 1e-6)
+		assert.InDelta(t, math.Sqrt(500.0*501/3), stats.StandardDeviation(), 1e-9)
+	}
+
+	t.Run("test empty", func(t *testing.T) {
+		for _, s := range []Float64Stream{NewSequentialFloat64Stream(nil), NewParallelFloat64Stream(nil)} {
+			stats, err := s.SummaryStatistics()
+			assert.NoError(t, err)
+			assert.Equal(t, Float64SummaryStatistics{}, *stats)
+			assert.Equal(t, 0.0, stats.Variance())
+			assert.Equal(t, 0.0, stats.SampleVariance())
+		}
+	})
+
+	t.Run("test combine", func(t *testing.T) {
+		var a, b, all Float64SummaryStatistics
+		for _, v := range []float64{2, 4, 4, 4} {
+			a.Accept(v)
+			all.Accept(v)
+		}
+		for _, v := range []float64{5, 5, 7, 9} {
+			b.Accept(v)
+			all.Accept(v)
+		}
+		a.Combine(&b)
+		assert.Equal(t, 8, a.Count())
+		assert.Equal(t, 40.0, a.Sum())
+		assert.Equal(t, 2.0, a.Min())
+		assert.Equal(t, 9.0, a.Max())
+		assert.Equal(t, 5.0, a.Average())
+		assert.Equal(t, 2.0, a.StandardDeviation())
+		assert.InDelta(t, all.Variance(), a.Variance(), 1e-12)
+
+		var empty Float64SummaryStatistics
+		empty.Combine(&a)
+		assert.Equal(t, a, empty)
+		a.Combine(&Float64SummaryStatistics{})
+		assert.Equal(t, empty, a)
+	})
+
+	t.Run("test error", func(t *testing.T) {
+		_, err := testErrIntStream.SummaryStatistics()
+		assert.Error(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = NewParallelIntStream(ints).WithContext(ctx).SummaryStatistics()
+		assert.Equal(t, context.Canceled, err)
+	})
+}
+
+func TestNumberStreamPercentile(t *testing.T) {
+	for _, s := range []IntStream{
+		NewSequentialIntStream([]int{5, 1, 4, 2, 3}),
+		NewParallelIntStream([]int{5, 1, 4, 2, 3}),
+		IntRangeClosed(1, 5).Map(func(src int) (dest int) {
+			return 6 - src
+		}),
+	} {
+		median, err := s.Median()
+		assert.NoError(t, err)
+		assert.Equal(t, 3.0, *median)
+		tests := []struct {
+			p      float64
+			expect float64
+		}{
+			{0, 1},
+			{25, 2},
+			{90, 4.6},
+			{100, 5},
+		}
+		for _, tt := range tests {
+			res, err := s.Percentile(tt.p)
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expect, *res, 1e-9)
+		}
+		_, err = s.Percentile(101)
+		assert.EqualError(t, err, "percentile error, p is out of [0, 100]: 101")
+		_, err = s.Percentile(math.NaN())
+		assert.Error(t, err)
+	}
+
+	median, err := NewSequentialFloat64Stream([]float64{1, 2, 3, 10}).Median()
+	assert.NoError(t, err)
+	assert.Equal(t, 2.5, *median)
+
+	median, err = NewParallelFloat64Stream(nil).Median()
+	assert.NoError(t, err)
+	assert.Nil(t, median)
+
+	_, err = testErrIntStream.Median()
+	assert.Error(t, err)
+	_, err = testErrIntStream.Percentile(50)
+	assert.Error(t, err)
+}
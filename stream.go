@@ -1,11 +1,10 @@
 package gostream
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
-	"sort"
-	"sync"
 )
 
 var (
@@ -17,43 +16,184 @@ type BaseStream interface {
 	// IsParallel returns whether this stream execute in parallel.
 	IsParallel() bool
 	// Err returns the error occurred in the stream, nil is returned if no error occurred.
+	// Errors raised while evaluating a lazy stream are reported after a terminal operation was called.
 	Err() error
 }
 
 // Stream is a sequence of elements supporting sequential and parallel aggregate operations.
+// Intermediate operations are lazy, they only build a pipeline of stages which is evaluated when a terminal operation,
+// such as Collect or Reduce, is called. Sequential stages pull the elements one at a time, so short-circuiting
+// operations like Limit stop the upstream work early.
 type Stream interface {
 	BaseStream
-	// 返回默认一条数据或者参数，没有返回类型的默认值
-	FirstOrDefault(obj interface{}) error
+	// AllMatch returns whether all the elements of this stream match predicate, true is returned if the stream is
+	// empty. The elements are no longer tested once an element doesn't match.
+	AllMatch(predicate func(val interface{}) (match bool)) (bool, error)
+	// AnyMatch returns whether any element of this stream matches predicate, false is returned if the stream is
+	// empty. The elements are no longer tested once an element matches.
+	AnyMatch(predicate func(val interface{}) (match bool)) (bool, error)
+	// AntiJoin returns a stream consisting of the elements of this stream whose keys don't match the key of any element
+	// of other, the keys are extracted and compared according to keys.
+	AntiJoin(other Stream, keys JoinKeys) Stream
+	// Chunk returns a stream consisting of the slices of n consecutive elements of this stream in encounter order, the
+	// last slice has fewer elements if the length of this stream is not a multiple of n. The slices are of type
+	// []interface{}. An error will occur if n is not positive.
+	Chunk(n int) Stream
+	// Chan returns a channel receiving the elements of this stream in encounter order. The elements are evaluated in a
+	// new goroutine, the channel is closed after all the elements were sent or an error occurred, and the error can
+	// be obtained by Err after the channel was closed.
+	Chan() <-chan interface{}
 	// Collect write the elements in the stream to the collector, the collector should be a pointer to Slice
 	// than can store the elements in the stream.
 	Collect(collector interface{}) error
+	// CollectWith performs a mutable reduction on the elements of this stream with collector, and returns the result
+	// of collector. A parallel stream accumulates the chunks of its elements concurrently and combines the partial
+	// results in encounter order.
+	CollectWith(collector Collector) (interface{}, error)
+	// Count returns the number of elements of this stream, it's known without evaluating the stream if the stream
+	// consists of the elements of a slice only.
+	Count() (int, error)
 	// Distinct returns a stream consisting of the distinct elements of this stream.
 	// hashcode should return the hashcode of obj, 2 equals object should return the same hashcode.
 	// equals should return true if a and b are equal, otherwise should return false.
 	Distinct(hashcode func(obj interface{}) interface{}, equals func(a, b interface{}) bool) Stream
+	// DropWhile returns a stream consisting of the remaining elements of this stream after dropping the longest prefix
+	// of elements matching predicate. The elements after the first mismatch are no longer tested.
+	DropWhile(predicate func(val interface{}) (match bool)) Stream
+	// ElementAt sets the value obj points to to the element at index of this stream in encounter order, only the
+	// elements up to index are evaluated. An error will occur if index is out of range.
+	ElementAt(index int, obj interface{}) error
 	// Filter returns a stream consisting of the elements of this stream that match the given predicate.
 	Filter(predicate func(val interface{}) (match bool)) Stream
+	// FilterE is the Filter whose predicate may fail, the errors returned are handled according to the error mode
+	// of this stream.
+	FilterE(predicate func(val interface{}) (match bool, err error)) Stream
 	// FlatMap returns a stream consisting of the results of replacing each element of this stream with the contents of
 	// a mapped stream produced by applying mapper to each element.
 	FlatMap(mapper func(val interface{}) Stream) Stream
+	// FlatMapE is the FlatMap whose mapper may fail, the errors returned are handled according to the error mode
+	// of this stream.
+	FlatMapE(mapper func(val interface{}) (Stream, error)) Stream
+	// FindAny returns the value describing some element of this stream, or nil if the stream is empty.
+	// A parallel stream may return any of its elements.
+	FindAny() (interface{}, error)
+	// FindFirst returns the value describing the first element of this stream, or nil if the stream is empty.
+	// The upstream elements are no longer evaluated once the first element was found.
+	FindFirst() (interface{}, error)
+	// FirstOrDefault sets the value obj points to to the first element of this stream, or to its zero value if the
+	// stream is empty. Only the first element is evaluated. obj should be a pointer to a variable the element is
+	// assignable to, or to which the value the element points to is assignable, otherwise an error will occur.
+	FirstOrDefault(obj interface{}) error
+	// ForEach performs action for each element of this stream. A parallel stream performs the actions concurrently in
+	// no particular order, so action should be safe for concurrent use.
+	ForEach(action func(val interface{})) error
+	// ForEachOrdered performs action for each element of this stream one at a time in encounter order, a parallel
+	// stream still evaluates the upstream stages concurrently.
+	ForEachOrdered(action func(val interface{})) error
+	// FullOuterJoin is the LeftJoin keeping the elements of other matching no element of this stream as well, they are
+	// joined with nil by result, and follow the other joined elements in the encounter order of other.
+	FullOuterJoin(other Stream, keys JoinKeys, result func(val, other interface{}) interface{}) Stream
+	// GroupBy returns a GroupedStream grouping the elements of this stream by the keys returned by key, the keys should
+	// be comparable. The groups are aggregated into rows by Aggregate of the GroupedStream.
+	GroupBy(key func(val interface{}) interface{}) GroupedStream
+	// Join returns a stream consisting of the results of applying result to each pair of the elements of this stream
+	// and the elements of other whose keys match, the keys are extracted and compared according to keys. The elements
+	// are joined by a hash join, the hash table is built from other, and then probed with the elements of this stream
+	// in encounter order, concurrently if this stream is parallel. The joined elements follow the encounter order of
+	// this stream, and then the one of other for the elements matching the same element.
+	Join(other Stream, keys JoinKeys, result func(val, other interface{}) interface{}) Stream
+	// LastOrDefault sets the value obj points to to the last element of this stream, or to its zero value if the
+	// stream is empty.
+	LastOrDefault(obj interface{}) error
+	// LeftJoin is the Join keeping the elements of this stream matching no element of other, which are joined with
+	// nil by result.
+	LeftJoin(other Stream, keys JoinKeys, result func(val, other interface{}) interface{}) Stream
 	// Limit returns a stream consisting of elements of this stream, truncated to be no longer than maxSize in length，
 	// An error will occur when maxSize is negative.
 	Limit(maxSize int) Stream
 	// Map returns a steam consisting of the results of applying mapper to the elements of this stream.
 	Map(mapper func(src interface{}) (dest interface{})) Stream
+	// MapE is the Map whose mapper may fail, the errors returned are handled according to the error mode of this
+	// stream.
+	MapE(mapper func(src interface{}) (dest interface{}, err error)) Stream
 	// MapToFloat64 returns a Float64Stream consisting of the results of applying the given mapper to the elements of
 	// this stream.
 	MapToFloat64(mapper func(src interface{}) (dest float64)) Float64Stream
 	// MapToInt returns an IntStream consisting of the results of applying the given mapper to the elements of
 	// this stream.
 	MapToInt(mapper func(src interface{}) (dest int)) IntStream
+	// MapToInt64 returns an Int64Stream consisting of the results of applying the given mapper to the elements of
+	// this stream.
+	MapToInt64(mapper func(src interface{}) (dest int64)) Int64Stream
+	// MapToString returns a StringStream consisting of the results of applying the given mapper to the elements of
+	// this stream.
+	MapToString(mapper func(src interface{}) (dest string)) StringStream
+	// Max returns the maximum element of this stream according to less, or nil if the stream is empty. The first one
+	// in encounter order is returned if several elements are the maximum. A parallel stream reduces its elements by
+	// the fork/join pool.
+	Max(less func(a, b interface{}) bool) (interface{}, error)
+	// Min returns the minimum element of this stream according to less, or nil if the stream is empty. The first one
+	// in encounter order is returned if several elements are the minimum. A parallel stream reduces its elements by
+	// the fork/join pool.
+	Min(less func(a, b interface{}) bool) (interface{}, error)
+	// NoneMatch returns whether no element of this stream matches predicate, true is returned if the stream is
+	// empty. The elements are no longer tested once an element matches.
+	NoneMatch(predicate func(val interface{}) (match bool)) (bool, error)
+	// Peek returns a stream consisting of the elements of this stream, additionally performing action on each element
+	// as the elements are consumed from the resulting stream. It's mainly to support debugging, a parallel stream
+	// performs the actions concurrently.
+	Peek(action func(val interface{})) Stream
 	// Reduce performs a reduction on the elements of this stream, using an associative accumulation function,
 	// and return the reduced value if any, otherwise nil will be returned.
 	// Reduction won't be performed if the stream contains an error, and the error will be returned.
 	Reduce(accumulator func(a, b interface{}) (c interface{})) (interface{}, error)
+	// ReduceTo performs a reduction on the elements of this stream into a result of another type: accumulator folds an
+	// element into a partial result, and combiner merges 2 partial results. identity is returned if the stream is
+	// empty. A parallel stream folds each partition of the elements starting from identity, so identity should be the
+	// identity of combiner and shouldn't be mutated, and merges the partial results in encounter order. The sequential
+	// stream doesn't use combiner.
+	ReduceTo(identity interface{}, accumulator func(acc, val interface{}) interface{},
+		combiner func(a, b interface{}) interface{}) (interface{}, error)
+	// ReduceWithIdentity performs a reduction on the elements of this stream, using identity and an associative
+	// accumulation function, and returns the reduced value, which is identity if the stream is empty. identity should
+	// be the identity of accumulator, since a parallel stream starts the reduction of each partition from it.
+	ReduceWithIdentity(identity interface{}, accumulator func(a, b interface{}) (c interface{})) (interface{}, error)
+	// SemiJoin returns a stream consisting of the elements of this stream whose keys match the key of any element of
+	// other, the keys are extracted and compared according to keys. Each element is kept once, no matter how many
+	// elements of other it matches.
+	SemiJoin(other Stream, keys JoinKeys) Stream
 	// Sorted returns a stream consisting of the elements of this stream, sorted according to less.
 	Sorted(less func(a, b interface{}) bool) Stream
+	// SortedBy returns a stream consisting of the elements of this stream, sorted according to comparator. The sort
+	// is stable, so the elements comparator considers equal keep their encounter order.
+	SortedBy(comparator Comparator[interface{}]) Stream
+	// Single sets the value obj points to to the only element of this stream, an error will occur if the stream
+	// doesn't have exactly one element. At most 2 elements are evaluated.
+	Single(obj interface{}) error
+	// SingleOrDefault sets the value obj points to to the only element of this stream, or to its zero value if the
+	// stream is empty. An error will occur if the stream has more than one element.
+	SingleOrDefault(obj interface{}) error
+	// TakeWhile returns a stream consisting of the longest prefix of elements of this stream matching predicate, the
+	// upstream elements are no longer evaluated once an element doesn't match. A parallel stream tests the elements
+	// concurrently in batches, and keeps the encounter order.
+	TakeWhile(predicate func(val interface{}) (match bool)) Stream
+	// ToChan sends the elements of this stream to ch in encounter order, ch should be a channel that can be sent to
+	// and whose element type can store the elements. ch is closed after all the elements were sent or an error
+	// occurred.
+	ToChan(ch interface{}) error
+	// Window returns a stream consisting of the sliding windows of size consecutive elements of this stream, a window
+	// starts every step elements, so the windows overlap if step is less than size. Only the full windows are
+	// produced, and they are of type []interface{}. An error will occur if size or step is not positive.
+	Window(size, step int) Stream
+	// WithContext returns an equivalent stream whose stages are evaluated with ctx, no more work is scheduled once
+	// ctx is done, and the error of ctx is reported by Err and the terminal operations.
+	WithContext(ctx context.Context) Stream
+	// WithErrorMode returns an equivalent stream handling the errors returned by the functions supplied to MapE,
+	// FilterE and FlatMapE according to mode. An error will occur if mode is unknown.
+	WithErrorMode(mode ErrorMode) Stream
+	// WithParallelism returns an equivalent stream whose parallel operations run on at most n goroutines, an error
+	// will occur if n is not positive. It has no effect on the sequential operations.
+	WithParallelism(n int) Stream
 	// Skip returns a stream consisting of the remaining elements of this stream after discarding
 	// the first n elements of the stream.
 	// If this stream contains fewer than n elements then an empty stream will be returned.
@@ -68,11 +208,11 @@ type Stream interface {
 }
 
 type sequentialStream struct {
-	elements []*element
+	pipeline
 }
 
 type parallelStream struct {
-	elements []*element
+	pipeline
 }
 
 type errStream struct {
@@ -94,7 +234,9 @@ func NewSequentialStream(data interface{}) Stream {
 	if len(elements) <= 0 {
 		return emptySequentialStream
 	}
-	return &sequentialStream{elements}
+	s := newSequentialStream(config{}, sliceStage(elements))
+	s.source = elements
+	return s
 }
 
 // NewSequentialStream returns a parallel stream whose elements are the specified data.
@@ -106,22 +248,98 @@ func NewParallelStream(data interface{}) Stream {
 	if len(elements) <= 0 {
 		return emptyParallelStream
 	}
-	return &parallelStream{elements}
+	p := newParallelStream(config{}, sliceStage(elements))
+	p.source = elements
+	return p
 }
 
 // ConcatStream creates a concatenated stream whose elements are all the elements of the first stream followed by all
 // the elements of the second stream.
 func ConcatStream(a, b Stream) (c Stream) {
-	var aSlice, bSlice []interface{}
-	if err := a.Collect(&aSlice); err != nil {
+	if err := a.Err(); err != nil {
 		return &errStream{err: err}
 	}
-	aElements, _ := convertDataToElements(aSlice)
-	if err := b.Collect(&bSlice); err != nil {
+	if err := b.Err(); err != nil {
 		return &errStream{err: err}
 	}
-	bElements, _ := convertDataToElements(bSlice)
-	return &sequentialStream{elements: append(aElements, bElements...)}
+	return newSequentialStream(config{}, concatStage(a, b))
+}
+
+// Zip creates a sequential stream whose elements are the results of applying combiner to the elements of a and b at
+// the same position, the stream is as long as the shorter one of a and b. The elements are Pair if combiner is nil.
+// The elements of a and b are pulled in lockstep, so an infinite stream can be zipped with a finite one.
+func Zip(a, b Stream, combiner func(first, second interface{}) interface{}) Stream {
+	if err := a.Err(); err != nil {
+		return &errStream{err: err}
+	}
+	if err := b.Err(); err != nil {
+		return &errStream{err: err}
+	}
+	if combiner == nil {
+		combiner = func(first, second interface{}) interface{} {
+			return Pair{First: first, Second: second}
+		}
+	}
+	return newSequentialStream(config{}, zipStage(a, b, combiner))
+}
+
+// Unzip splits a stream of Pair into two sequential streams, consisting of the first and the second elements of the
+// pairs respectively. s is evaluated only once, when either stream is evaluated for the first time. An error will occur
+// if an element of s is not a Pair.
+func Unzip(s Stream) (first, second Stream) {
+	if err := s.Err(); err != nil {
+		return &errStream{err: err}, &errStream{err: err}
+	}
+	source := sharedStage(s)
+	first = newSequentialStream(config{}, mapEStage(source, StopOnFirstError, func(src interface{}) (interface{}, error) {
+		pair, err := assertPair(src)
+		return pair.First, err
+	}))
+	second = newSequentialStream(config{}, mapEStage(source, StopOnFirstError, func(src interface{}) (interface{}, error) {
+		pair, err := assertPair(src)
+		return pair.Second, err
+	}))
+	return first, second
+}
+
+// Pair is the element of the stream zipped without a combiner.
+type Pair struct {
+	First  interface{}
+	Second interface{}
+}
+
+func assertPair(val interface{}) (Pair, error) {
+	pair, ok := val.(Pair)
+	if !ok {
+		return pair, fmt.Errorf("unzip error, element is %T but not Pair", val)
+	}
+	return pair, nil
+}
+
+func newSequentialStream(cfg config, tail stage) *sequentialStream {
+	return &sequentialStream{pipeline: pipeline{tail: tail, config: cfg}}
+}
+
+func newParallelStream(cfg config, tail stage) *parallelStream {
+	return &parallelStream{pipeline: pipeline{tail: tail, config: cfg}}
+}
+
+// sequential returns a sequential stream consisting of the stages of p with cfg.
+func (p *pipeline) sequential(cfg config) *sequentialStream {
+	s := newSequentialStream(cfg, p.tail)
+	s.source = p.source
+	return s
+}
+
+// parallel returns a parallel stream consisting of the stages of p with cfg.
+func (p *pipeline) parallel(cfg config) *parallelStream {
+	s := newParallelStream(cfg, p.tail)
+	s.source = p.source
+	return s
+}
+
+func newElement(data interface{}) *element {
+	return &element{data: data, reflectValue: reflect.ValueOf(data)}
 }
 
 func convertDataToElements(data interface{}) ([]*element, error) {
@@ -141,66 +359,136 @@ func convertDataToElements(data interface{}) ([]*element, error) {
 	return elements, nil
 }
 
-func (s *sequentialStream) MapToFloat64(mapper func(src interface{}) (dest float64)) Float64Stream {
-	if len(s.elements) <= 0 {
-		return emptySequentialFloat64Stream
+// collectElements packs elements into collector, which should be a pointer to slice.
+func collectElements(elements []*element, collector interface{}) (err error) {
+	defer func() {
+		// 当stream内的元素类型不能赋值到collector中时会产生panic，要recover处理掉
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic when pack results into collector, recover=%v", r)
+		}
+	}()
+
+	collectorReflectValue := reflect.ValueOf(collector)
+	if collectorReflectValue.Kind() != reflect.Ptr || collectorReflectValue.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("cannot pack results into collector, collector is a %s but not a slice pointer", collectorReflectValue.Type())
 	}
-	newElements := make([]float64, 0, len(s.elements))
-	for _, e := range s.elements {
-		newElements = append(newElements, mapper(e.data))
+	results := collectorReflectValue.Elem()
+	results.Set(reflect.MakeSlice(results.Type(), 0, len(elements)))
+	for _, elem := range elements {
+		results.Set(reflect.Append(results, elementValue(elem)))
 	}
-	return &sequentialFloat64Stream{newElements}
+	return nil
 }
 
-func (s *sequentialStream) MapToInt(mapper func(src interface{}) (dest int)) IntStream {
-	if len(s.elements) <= 0 {
-		return emptySequentialIntStream
-	}
-	newElements := make([]int, 0, len(s.elements))
-	for _, e := range s.elements {
-		newElements = append(newElements, mapper(e.data))
+// elementValue returns the reflect.Value of the data of e, the dynamic value is returned if the data is stored in
+// an interface.
+func elementValue(e *element) reflect.Value {
+	if e.reflectValue.Kind() == reflect.Interface {
+		return e.reflectValue.Elem()
 	}
-	return &sequentialIntStream{newElements}
+	return e.reflectValue
+}
+
+// sequentialStreamToNumber returns a sequential stream consisting of the results of applying mapper to the elements of
+// s, op is the name of the operation reported by the *PanicError. The elements are mapped once they are pulled.
+func sequentialStreamToNumber[N Number](s *sequentialStream, op string, mapper func(src interface{}) (dest N)) NumberStream[N] {
+	return newLazyNumberStream[N](newSequentialStream(s.config, mapStage(s.tail, op, func(src interface{}) (dest interface{}) {
+		return mapper(src)
+	})))
+}
+
+// parallelStreamToNumber returns a parallel stream consisting of the results of applying mapper to the elements of p
+// concurrently, op is the name of the operation reported by the *PanicError. The elements are mapped once a terminal
+// operation evaluates the stream.
+func parallelStreamToNumber[N Number](p *parallelStream, op string, mapper func(src interface{}) (dest N)) NumberStream[N] {
+	return newLazyNumberStream[N](newParallelStream(p.config, p.parallelMapStage(op, func(src interface{}) (dest interface{}) {
+		return mapper(src)
+	})))
+}
+
+func (s *sequentialStream) MapToFloat64(mapper func(src interface{}) (dest float64)) Float64Stream {
+	return sequentialStreamToNumber(s, "MapToFloat64", mapper)
+}
+
+func (s *sequentialStream) MapToInt(mapper func(src interface{}) (dest int)) IntStream {
+	return sequentialStreamToNumber(s, "MapToInt", mapper)
+}
+
+func (s *sequentialStream) MapToInt64(mapper func(src interface{}) (dest int64)) Int64Stream {
+	return sequentialStreamToNumber(s, "MapToInt64", mapper)
+}
+
+func (s *sequentialStream) MapToString(mapper func(src interface{}) (dest string)) StringStream {
+	return newStringStream(s.Map(func(src interface{}) (dest interface{}) {
+		return mapper(src)
+	}))
 }
 
 func (s *sequentialStream) IsParallel() bool {
 	return false
 }
 
+func (s *sequentialStream) WithContext(ctx context.Context) Stream {
+	return s.sequential(s.withContext(ctx))
+}
+
+func (s *sequentialStream) WithParallelism(n int) Stream {
+	if n <= 0 {
+		return &errStream{err: fmt.Errorf("parallelism error, n is not positive: %v", n)}
+	}
+	return s.sequential(s.withParallelism(n))
+}
+
+func (s *sequentialStream) WithErrorMode(mode ErrorMode) Stream {
+	if err := checkErrorMode(mode); err != nil {
+		return &errStream{err: err}
+	}
+	return s.sequential(s.withErrorMode(mode))
+}
+
 func (s *sequentialStream) Sequential() Stream {
 	return s
 }
 
 func (s *sequentialStream) Parallel() Stream {
-	if len(s.elements) <= 0 {
-		return emptyParallelStream
-	}
-	return &parallelStream{s.elements}
+	return s.parallel(s.config)
 }
 
 func (s *sequentialStream) FlatMap(mapper func(val interface{}) Stream) Stream {
-	if len(s.elements) == 0 {
+	if s.tail == nil {
 		return s
 	}
-
-	newElements := make([]*element, 0, len(s.elements))
-	for _, e := range s.elements {
-		d := mapper(e.data)
-		newElements = append(newElements, &element{data: d, reflectValue: reflect.ValueOf(d)})
-	}
-	result, _ := (&sequentialStream{elements: newElements}).Reduce(func(a, b interface{}) (c interface{}) {
-		return ConcatStream(a.(Stream), b.(Stream))
-	})
-	return result.(Stream)
+	return newSequentialStream(s.config, flatMapStage(s.tail, mapper))
 }
 
 func (s *sequentialStream) Reduce(accumulator func(a, b interface{}) (c interface{})) (interface{}, error) {
-	if len(s.elements) == 0 {
-		return nil, nil
+	return s.reduce("Reduce", accumulator)
+}
+
+// reduce reduces the elements with accumulator one at a time, op is the name of the operation reported by the
+// *PanicError.
+func (s *sequentialStream) reduce(op string, accumulator func(a, b interface{}) (c interface{})) (interface{}, error) {
+	it := s.iterator()
+	first, ok := it.next()
+	if !ok {
+		err := it.err()
+		s.record(err)
+		return nil, err
+	}
+	identity := first.data
+	index := 1
+	err := guardAt(op, &index, func() {
+		for e, ok := it.next(); ok; e, ok = it.next() {
+			identity = accumulator(identity, e.data)
+			index++
+		}
+	})
+	if err == nil {
+		err = it.err()
 	}
-	identity := s.elements[0].data
-	for i := 1; i < len(s.elements); i++ {
-		identity = accumulator(identity, s.elements[i].data)
+	if err != nil {
+		s.record(err)
+		return nil, err
 	}
 	return identity, nil
 }
@@ -209,10 +497,10 @@ func (s *sequentialStream) Skip(n int) Stream {
 	if n < 0 {
 		return &errStream{err: fmt.Errorf("skip error, n less than 0: %d", n)}
 	}
-	if len(s.elements) <= n {
-		return emptySequentialStream
+	if n == 0 || s.tail == nil {
+		return s
 	}
-	return &sequentialStream{elements: s.elements[n:]}
+	return newSequentialStream(s.config, skipStage(s.tail, n))
 }
 
 func (s *sequentialStream) Limit(maxSize int) Stream {
@@ -222,232 +510,157 @@ func (s *sequentialStream) Limit(maxSize int) Stream {
 	if maxSize == 0 {
 		return emptySequentialStream
 	}
-	if len(s.elements) <= maxSize {
+	if s.tail == nil {
 		return s
 	}
-	return &sequentialStream{elements: s.elements[:maxSize]}
+	return newSequentialStream(s.config, limitStage(s.tail, maxSize))
 }
 
 func (s *sequentialStream) Filter(predicate func(val interface{}) (keep bool)) Stream {
-	remain := make([]*element, 0)
-	for _, elem := range s.elements {
-		if predicate(elem.data) {
-			remain = append(remain, elem)
-		}
-	}
-	if len(remain) <= 0 {
-		return emptySequentialStream
+	if s.tail == nil {
+		return s
 	}
-	return &sequentialStream{remain}
+	return newSequentialStream(s.config, filterStage(s.tail, predicate))
 }
 
 func (s *sequentialStream) Map(mapper func(src interface{}) (dest interface{})) Stream {
-	if len(s.elements) <= 0 {
+	if s.tail == nil {
 		return s
 	}
-	newElements := make([]*element, 0)
-	for _, elem := range s.elements {
-		newData := mapper(elem.data)
-		newElements = append(newElements, &element{
-			data:         newData,
-			reflectValue: reflect.ValueOf(newData),
-		})
-	}
-	return &sequentialStream{elements: newElements}
+	return newSequentialStream(s.config, mapStage(s.tail, "Map", mapper))
 }
 
-func (s *sequentialStream) Sorted(less func(a, b interface{}) bool) Stream {
-	if len(s.elements) <= 1 {
+func (s *sequentialStream) FilterE(predicate func(val interface{}) (match bool, err error)) Stream {
+	if s.tail == nil {
 		return s
 	}
-	newElements := make([]*element, 0, len(s.elements))
-	newElements = append(newElements, s.elements...)
-	sort.Slice(newElements, func(i, j int) bool {
-		return less(newElements[i].data, newElements[j].data)
-	})
-	return &sequentialStream{elements: newElements}
+	return newSequentialStream(s.config, filterEStage(s.tail, s.errorMode, predicate))
 }
 
-func (s *sequentialStream) Distinct(hashcode func(obj interface{}) interface{}, equals func(a, b interface{}) bool) Stream {
-	if len(s.elements) <= 1 {
+func (s *sequentialStream) MapE(mapper func(src interface{}) (dest interface{}, err error)) Stream {
+	if s.tail == nil {
 		return s
 	}
-	elementMap := make(map[interface{}][]interface{}, len(s.elements))
-	newElements := make([]*element, 0)
-	for _, elem := range s.elements {
-		code := hashcode(elem.data)
-		duplicated := false
-		seens := elementMap[code]
-		for _, seen := range seens {
-			if equals(elem.data, seen) {
-				duplicated = true
-				break
-			}
-		}
-		if !duplicated {
-			elementMap[code] = append(seens, elem.data)
-			newElements = append(newElements, elem)
-		}
-	}
-	return &sequentialStream{elements: newElements}
+	return newSequentialStream(s.config, mapEStage(s.tail, s.errorMode, mapper))
 }
 
-func (s *sequentialStream) FirstOrDefault(obj interface{}) (err error) {
-	defer func() {
-		// 当stream内的元素类型不能赋值到collector中时会产生panic，要recover处理掉
-		if r := recover(); r != nil {
-			err = fmt.Errorf("panic when elemnt result into object, recover=%v", r)
-		}
-	}()
-
-	objReflectValue := reflect.ValueOf(obj)
-	kind := objReflectValue.Elem().Kind()
-	result := objReflectValue.Elem()
-
-	if len(s.elements) <= 0 {
-		if kind == reflect.Struct {
-			obj = nil
-		}
-		return nil
+func (s *sequentialStream) FlatMapE(mapper func(val interface{}) (Stream, error)) Stream {
+	if s.tail == nil {
+		return s
 	}
+	return newSequentialStream(s.config, flatMapEStage(s.tail, "FlatMapE", s.errorMode, mapper))
+}
 
-	defaultElement := s.elements[0]
+func (s *sequentialStream) DropWhile(predicate func(val interface{}) (match bool)) Stream {
+	if s.tail == nil {
+		return s
+	}
+	return newSequentialStream(s.config, whileStage(s.tail, "DropWhile", 1, false, predicate))
+}
 
-	if reflectValue := defaultElement.reflectValue; reflectValue.Kind() == reflect.Interface || kind == reflect.Struct {
-		result.Set(reflectValue.Elem())
-	} else {
-		result.Set(reflectValue)
+func (s *sequentialStream) TakeWhile(predicate func(val interface{}) (match bool)) Stream {
+	if s.tail == nil {
+		return s
 	}
+	return newSequentialStream(s.config, whileStage(s.tail, "TakeWhile", 1, true, predicate))
+}
 
-	return nil
+func (s *sequentialStream) Sorted(less func(a, b interface{}) bool) Stream {
+	if s.tail == nil {
+		return s
+	}
+	return newSequentialStream(s.config, sortedStage(s.tail, less))
 }
 
-func (s *sequentialStream) Collect(collector interface{}) (err error) {
-	defer func() {
-		// 当stream内的元素类型不能赋值到collector中时会产生panic，要recover处理掉
-		if r := recover(); r != nil {
-			err = fmt.Errorf("panic when pack results into collector, recover=%v", r)
-		}
-	}()
+func (s *sequentialStream) SortedBy(comparator Comparator[interface{}]) Stream {
+	return s.Sorted(comparator.Less)
+}
 
-	collectorReflectValue := reflect.ValueOf(collector)
-	if collectorReflectValue.Kind() != reflect.Ptr || collectorReflectValue.Elem().Kind() != reflect.Slice {
-		return fmt.Errorf("cannot pack results into collector, collector is a %s but not a slice pointer", collectorReflectValue.Type())
-	}
-	results := collectorReflectValue.Elem()
-	results.Set(reflect.MakeSlice(results.Type(), 0, len(s.elements)))
-	for _, elem := range s.elements {
-		if reflectValue := elem.reflectValue; reflectValue.Kind() == reflect.Interface {
-			results.Set(reflect.Append(results, reflectValue.Elem()))
-		} else {
-			results.Set(reflect.Append(results, reflectValue))
-		}
+func (s *sequentialStream) Distinct(hashcode func(obj interface{}) interface{}, equals func(a, b interface{}) bool) Stream {
+	if s.tail == nil {
+		return s
 	}
-	return nil
+	return newSequentialStream(s.config, distinctStage(s.tail, hashcode, equals))
 }
 
-func (s *sequentialStream) Err() error {
-	return nil
+func (s *sequentialStream) Collect(collector interface{}) (err error) {
+	elements, err := s.evaluate()
+	if err != nil {
+		return err
+	}
+	return collectElements(elements, collector)
 }
 
 func (p *parallelStream) IsParallel() bool {
 	return true
 }
 
-func (p *parallelStream) FirstOrDefault(obj interface{}) error {
+func (p *parallelStream) WithContext(ctx context.Context) Stream {
+	return p.parallel(p.withContext(ctx))
+}
 
-	return nil
+func (p *parallelStream) WithParallelism(n int) Stream {
+	if n <= 0 {
+		return &errStream{err: fmt.Errorf("parallelism error, n is not positive: %v", n), parallel: true}
+	}
+	return p.parallel(p.withParallelism(n))
 }
 
-func (p *parallelStream) Collect(collector interface{}) (err error) {
-	defer func() {
-		// 当stream内的元素类型不能赋值到collector中时会产生panic，要recover处理掉
-		if r := recover(); r != nil {
-			err = fmt.Errorf("panic when pack results into collector, recover=%v", r)
-		}
-	}()
-	collectorReflectValue := reflect.ValueOf(collector)
-	if collectorReflectValue.Kind() != reflect.Ptr || collectorReflectValue.Elem().Kind() != reflect.Slice {
-		return fmt.Errorf("cannot pack results into collector, collector is a %s but not a slice pointer", collectorReflectValue.Type())
+func (p *parallelStream) WithErrorMode(mode ErrorMode) Stream {
+	if err := checkErrorMode(mode); err != nil {
+		return &errStream{err: err, parallel: true}
 	}
-	results := collectorReflectValue.Elem()
-	results.Set(reflect.MakeSlice(results.Type(), 0, len(p.elements)))
-	for _, elem := range p.elements {
-		if reflectValue := elem.reflectValue; reflectValue.Kind() == reflect.Interface {
-			results.Set(reflect.Append(results, reflectValue.Elem()))
-		} else {
-			results.Set(reflect.Append(results, reflectValue))
-		}
+	return p.parallel(p.withErrorMode(mode))
+}
+
+func (p *parallelStream) Collect(collector interface{}) (err error) {
+	elements, err := p.evaluate()
+	if err != nil {
+		return err
 	}
-	return nil
+	return collectElements(elements, collector)
 }
 
 func (p *parallelStream) Distinct(hashcode func(obj interface{}) interface{}, equals func(a, b interface{}) bool) Stream {
-	if len(p.elements) <= 1 {
+	if p.tail == nil {
 		return p
 	}
-	elementMap := make(map[interface{}][]interface{}, len(p.elements))
-	newElements := make([]*element, 0)
-	for _, elem := range p.elements {
-		code := hashcode(elem.data)
-		duplicated := false
-		seens := elementMap[code]
-		for _, seen := range seens {
-			if equals(elem.data, seen) {
-				duplicated = true
-				break
-			}
-		}
-		if !duplicated {
-			elementMap[code] = append(seens, elem.data)
-			newElements = append(newElements, elem)
-		}
-	}
-	return &parallelStream{elements: newElements}
-}
-
-func (p *parallelStream) Err() error {
-	return nil
+	return newParallelStream(p.config, distinctStage(p.tail, hashcode, equals))
 }
 
 func (p *parallelStream) Filter(predicate func(val interface{}) (match bool)) Stream {
-	if len(p.elements) == 0 {
+	if p.tail == nil {
 		return p
 	}
-	remain := make([]*element, 0)
-	var wg sync.WaitGroup
-	wg.Add(len(p.elements) - 1)
-	matches := make([]bool, len(p.elements))
-	for i := 1; i < len(p.elements); i++ {
-		index := i
-		go func() {
-			defer wg.Done()
-			matches[index] = predicate(p.elements[index].data)
-		}()
-	}
-	if elem := p.elements[0]; predicate(elem.data) {
-		remain = append(remain, elem)
-	}
-	wg.Wait()
-	for i := 1; i < len(p.elements); i++ {
-		if matches[i] {
-			remain = append(remain, p.elements[i])
+	return newParallelStream(p.config, batchStage(p.tail, p.parallelism, func(ctx context.Context, offset int,
+		elements []*element) ([]*element, error) {
+		matches := make([]bool, len(elements))
+		err := parallelEachAt(ctx, "Filter", p.parallelism, offset, len(elements), func(i int) {
+			matches[i] = predicate(elements[i].data)
+		})
+		if err != nil {
+			return nil, err
 		}
-	}
-	return &parallelStream{elements: remain}
+		remain := make([]*element, 0)
+		for i, elem := range elements {
+			if matches[i] {
+				remain = append(remain, elem)
+			}
+		}
+		return remain, nil
+	}))
 }
 
 func (p *parallelStream) FlatMap(mapper func(val interface{}) Stream) Stream {
-	if len(p.elements) == 0 {
+	if p.tail == nil {
 		return p
 	}
-	result, _ := p.Map(func(src interface{}) (dest interface{}) {
-		dest = mapper(src)
-		return dest
-	}).Reduce(func(a, b interface{}) (c interface{}) {
-		return ConcatStream(a.(Stream), b.(Stream))
+	streams := p.parallelMapStage("FlatMap", func(src interface{}) (dest interface{}) {
+		return mapper(src)
 	})
-	return result.(Stream).Parallel()
+	return newParallelStream(p.config, flatMapStage(streams, func(val interface{}) Stream {
+		return val.(Stream)
+	}))
 }
 
 func (p *parallelStream) Limit(maxSize int) Stream {
@@ -457,120 +670,200 @@ func (p *parallelStream) Limit(maxSize int) Stream {
 	if maxSize == 0 {
 		return emptyParallelStream
 	}
-	if maxSize >= len(p.elements) {
+	if p.tail == nil {
 		return p
 	}
-	return &parallelStream{p.elements[:maxSize]}
-
+	return newParallelStream(p.config, limitStage(p.tail, maxSize))
 }
 
 func (p *parallelStream) Map(mapper func(src interface{}) (dest interface{})) Stream {
-	length := len(p.elements)
-	if length == 0 {
+	if p.tail == nil {
 		return p
 	}
-	calculateElement := func(old *element) (new *element) {
-		newData := mapper(old.data)
-		return &element{data: newData, reflectValue: reflect.ValueOf(newData)}
-	}
+	return newParallelStream(p.config, p.parallelMapStage("Map", mapper))
+}
 
-	newElements := make([]*element, len(p.elements))
-	var wg sync.WaitGroup
-	wg.Add(length - 1)
-	for i := 1; i < length; i++ {
-		index := i
-		go func() {
-			defer wg.Done()
-			newElements[index] = calculateElement(p.elements[index])
-		}()
+func (p *parallelStream) FilterE(predicate func(val interface{}) (match bool, err error)) Stream {
+	if p.tail == nil {
+		return p
 	}
-	newElements[0] = calculateElement(p.elements[0])
-	wg.Wait()
-	return &parallelStream{newElements}
+	return newParallelStream(p.config, batchStage(p.tail, p.parallelism, func(ctx context.Context, offset int,
+		elements []*element) ([]*element, error) {
+		matches := make([]bool, len(elements))
+		err := p.withContext(ctx).eachEAt("FilterE", true, offset, len(elements), func(i int) error {
+			match, err := predicate(elements[i].data)
+			matches[i] = match && err == nil
+			return err
+		})
+		if err != nil && !isCollected(err) {
+			return nil, err
+		}
+		remain := make([]*element, 0)
+		for i, elem := range elements {
+			if matches[i] {
+				remain = append(remain, elem)
+			}
+		}
+		return remain, err
+	}))
 }
 
-func (p *parallelStream) MapToFloat64(mapper func(src interface{}) (dest float64)) Float64Stream {
-	length := len(p.elements)
-	if length == 0 {
-		return emptyParallelFloat64Stream
+func (p *parallelStream) MapE(mapper func(src interface{}) (dest interface{}, err error)) Stream {
+	if p.tail == nil {
+		return p
 	}
-	newElements := make([]float64, length)
-	var wg sync.WaitGroup
-	wg.Add(length - 1)
-	for i := 1; i < length; i++ {
-		index := i
-		go func() {
-			defer wg.Done()
-			newElements[index] = mapper(p.elements[index].data)
-		}()
+	return newParallelStream(p.config, p.parallelMapEStage("MapE", mapper))
+}
+
+func (p *parallelStream) FlatMapE(mapper func(val interface{}) (Stream, error)) Stream {
+	if p.tail == nil {
+		return p
 	}
-	newElements[0] = mapper(p.elements[0].data)
-	wg.Wait()
-	return &parallelFloat64Stream{newElements}
+	streams := p.parallelMapEStage("FlatMapE", func(src interface{}) (dest interface{}, err error) {
+		return mapper(src)
+	})
+	return newParallelStream(p.config, flatMapStage(streams, func(val interface{}) Stream {
+		return val.(Stream)
+	}))
+}
+
+// parallelMapEStage is the parallelMapStage whose mapper may fail, the errors returned are handled according to the
+// error mode of the stream.
+func (p *parallelStream) parallelMapEStage(op string, mapper func(src interface{}) (dest interface{}, err error)) stage {
+	return batchStage(p.tail, p.parallelism, func(ctx context.Context, offset int, elements []*element) ([]*element, error) {
+		results := make([]*element, len(elements))
+		err := p.withContext(ctx).eachEAt(op, true, offset, len(elements), func(i int) error {
+			dest, err := mapper(elements[i].data)
+			if err == nil {
+				results[i] = newElement(dest)
+			}
+			return err
+		})
+		if err != nil && !isCollected(err) {
+			return nil, err
+		}
+		// the failed elements are left nil, which are dropped if the errors are collected
+		newElements := make([]*element, 0, len(results))
+		for _, e := range results {
+			if e != nil {
+				newElements = append(newElements, e)
+			}
+		}
+		return newElements, err
+	})
+}
+
+// parallelMapStage returns a stage applying mapper to the elements of the stream concurrently, op is the name of the
+// operation reported when mapper panics.
+func (p *parallelStream) parallelMapStage(op string, mapper func(src interface{}) (dest interface{})) stage {
+	return batchStage(p.tail, p.parallelism, func(ctx context.Context, offset int, elements []*element) ([]*element, error) {
+		newElements := make([]*element, len(elements))
+		err := parallelEachAt(ctx, op, p.parallelism, offset, len(elements), func(i int) {
+			newElements[i] = newElement(mapper(elements[i].data))
+		})
+		if err != nil {
+			return nil, err
+		}
+		return newElements, nil
+	})
+}
+
+func (p *parallelStream) MapToFloat64(mapper func(src interface{}) (dest float64)) Float64Stream {
+	return parallelStreamToNumber(p, "MapToFloat64", mapper)
 }
 
 func (p *parallelStream) MapToInt(mapper func(src interface{}) (dest int)) IntStream {
-	length := len(p.elements)
-	if length == 0 {
-		return emptyParallelIntStream
-	}
-	newElements := make([]int, length)
-	var wg sync.WaitGroup
-	wg.Add(length - 1)
-	for i := 1; i < length; i++ {
-		index := i
-		go func() {
-			defer wg.Done()
-			newElements[index] = mapper(p.elements[index].data)
-		}()
-	}
-	newElements[0] = mapper(p.elements[0].data)
-	wg.Wait()
-	return &parallelIntStream{newElements}
+	return parallelStreamToNumber(p, "MapToInt", mapper)
+}
+
+func (p *parallelStream) MapToInt64(mapper func(src interface{}) (dest int64)) Int64Stream {
+	return parallelStreamToNumber(p, "MapToInt64", mapper)
+}
+
+func (p *parallelStream) MapToString(mapper func(src interface{}) (dest string)) StringStream {
+	return newStringStream(p.Map(func(src interface{}) (dest interface{}) {
+		return mapper(src)
+	}))
 }
 
 func (p *parallelStream) Reduce(accumulator func(a, b interface{}) (c interface{})) (interface{}, error) {
-	if len(p.elements) == 0 {
+	return p.reduce("Reduce", accumulator)
+}
+
+// reduce reduces the elements with accumulator by the fork/join pool, op is the name of the operation reported by the
+// *PanicError.
+func (p *parallelStream) reduce(op string, accumulator func(a, b interface{}) (c interface{})) (interface{}, error) {
+	elements, err := p.evaluate()
+	if err != nil {
+		return nil, err
+	}
+	if len(elements) == 0 {
 		return nil, nil
 	}
-	if len(p.elements) == 1 {
-		return p.elements[0].data, nil
+	if len(elements) == 1 {
+		return elements[0].data, nil
+	}
+	var result interface{}
+	err = guard(op, -1, func() {
+		result = DefaultForkJoinPool().Invoke(newReduceRecursiveTask(op, accumulator, elements, 0, len(elements)-1))
+	})
+	if err == nil {
+		err = ctxErr(p.ctx)
+	}
+	if err != nil {
+		p.record(err)
+		return nil, err
 	}
-	f := newReduceRecursiveTask(accumulator, p.elements, 0, len(p.elements)-1)
-	return f.compute(), nil
+	return result, nil
 }
 
 func (p *parallelStream) Sorted(less func(a, b interface{}) bool) Stream {
-	if len(p.elements) <= 1 {
+	if p.tail == nil {
 		return p
 	}
-	newElements := make([]*element, len(p.elements))
-	copy(newElements, p.elements)
-	f := newSortRecursiveAction(func(a, b *element) bool {
-		return less(a.data, b.data)
-	}, newElements, make([]*element, len(p.elements)), 0, len(newElements)-1)
-	f.compute()
-	return &parallelStream{newElements}
+	return newParallelStream(p.config, barrierStage(p.tail, func(_ context.Context, elements []*element) ([]*element, error) {
+		if len(elements) <= 1 {
+			return elements, nil
+		}
+		err := guard("Sorted", -1, func() {
+			DefaultForkJoinPool().Invoke(newSortRecursiveAction(func(a, b *element) bool {
+				return less(a.data, b.data)
+			}, elements, make([]*element, len(elements)), 0, len(elements)-1))
+		})
+		return elements, err
+	}))
+}
+
+func (p *parallelStream) SortedBy(comparator Comparator[interface{}]) Stream {
+	return p.Sorted(comparator.Less)
+}
+
+func (p *parallelStream) DropWhile(predicate func(val interface{}) (match bool)) Stream {
+	if p.tail == nil {
+		return p
+	}
+	return newParallelStream(p.config, whileStage(p.tail, "DropWhile", p.parallelism, false, predicate))
+}
+
+func (p *parallelStream) TakeWhile(predicate func(val interface{}) (match bool)) Stream {
+	if p.tail == nil {
+		return p
+	}
+	return newParallelStream(p.config, whileStage(p.tail, "TakeWhile", p.parallelism, true, predicate))
 }
 
 func (p *parallelStream) Skip(n int) Stream {
 	if n < 0 {
 		return &errStream{err: errors.New("skip error, n is negative")}
 	}
-	if n == 0 {
+	if n == 0 || p.tail == nil {
 		return p
 	}
-	if n >= len(p.elements) {
-		return emptyParallelStream
-	}
-	return &parallelStream{elements: p.elements[n:]}
+	return newParallelStream(p.config, skipStage(p.tail, n))
 }
 
 func (p *parallelStream) Sequential() Stream {
-	if len(p.elements) <= 0 {
-		return emptySequentialStream
-	}
-	return &sequentialStream{p.elements}
+	return p.sequential(p.config)
 }
 
 func (p *parallelStream) Parallel() Stream {
@@ -585,10 +878,30 @@ func (e *errStream) MapToInt(func(src interface{}) (dest int)) IntStream {
 	return &errIntStream{err: e.err, parallel: e.parallel}
 }
 
+func (e *errStream) MapToInt64(func(src interface{}) (dest int64)) Int64Stream {
+	return &errInt64Stream{err: e.err, parallel: e.parallel}
+}
+
+func (e *errStream) MapToString(func(src interface{}) (dest string)) StringStream {
+	return newStringStream(e)
+}
+
 func (e *errStream) IsParallel() bool {
 	return e.parallel
 }
 
+func (e *errStream) WithContext(context.Context) Stream {
+	return e
+}
+
+func (e *errStream) WithParallelism(int) Stream {
+	return e
+}
+
+func (e *errStream) WithErrorMode(ErrorMode) Stream {
+	return e
+}
+
 func (e *errStream) Sequential() Stream {
 	if e.parallel {
 		return &errStream{err: e.err, parallel: false}
@@ -603,10 +916,6 @@ func (e *errStream) Parallel() Stream {
 	return &errStream{err: e.err, parallel: true}
 }
 
-func (e *errStream) FirstOrDefault(interface{}) error {
-	return e.err
-}
-
 func (e *errStream) Collect(interface{}) error {
 	return e.err
 }
@@ -635,6 +944,18 @@ func (e *errStream) Map(func(src interface{}) (dest interface{})) Stream {
 	return e
 }
 
+func (e *errStream) MapE(func(src interface{}) (dest interface{}, err error)) Stream {
+	return e
+}
+
+func (e *errStream) FilterE(func(val interface{}) (match bool, err error)) Stream {
+	return e
+}
+
+func (e *errStream) FlatMapE(func(val interface{}) (Stream, error)) Stream {
+	return e
+}
+
 func (e *errStream) Reduce(func(a, b interface{}) (c interface{})) (interface{}, error) {
 	return nil, e.err
 }
@@ -643,6 +964,18 @@ func (e *errStream) Sorted(func(a, b interface{}) bool) Stream {
 	return e
 }
 
+func (e *errStream) SortedBy(Comparator[interface{}]) Stream {
+	return e
+}
+
+func (e *errStream) DropWhile(func(val interface{}) (match bool)) Stream {
+	return e
+}
+
+func (e *errStream) TakeWhile(func(val interface{}) (match bool)) Stream {
+	return e
+}
+
 func (e *errStream) Skip(int) Stream {
 	return e
 }
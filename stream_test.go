@@ -8,6 +8,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"math/rand"
 	"reflect"
+	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -36,6 +38,110 @@ func TestConcatStream(t *testing.T) {
 	})
 }
 
+func TestZip(t *testing.T) {
+	sum := func(first, second interface{}) interface{} {
+		return first.(int) + second.(int)
+	}
+
+	t.Run("test a error", func(t *testing.T) {
+		assert.Error(t, Zip(testErrStream, NewSequentialStream([]int{1}), sum).Err())
+	})
+
+	t.Run("test b error", func(t *testing.T) {
+		assert.Error(t, Zip(NewSequentialStream([]int{1}), testErrStream, sum).Err())
+	})
+
+	tests := []struct {
+		name   string
+		a, b   Stream
+		expect []int
+	}{
+		{"test same length", NewSequentialStream([]int{1, 2, 3}), NewParallelStream([]int{10, 20, 30}), []int{11, 22, 33}},
+		{"test a shorter", NewSequentialStream([]int{1, 2}), NewSequentialStream([]int{10, 20, 30}), []int{11, 22}},
+		{"test b shorter", NewParallelStream([]int{1, 2, 3}), NewSequentialStream([]int{10}), []int{11}},
+		{"test empty", NewSequentialStream([]int{}), NewSequentialStream([]int{10}), []int{}},
+		{
+			"test infinite",
+			Iterate(0, nil, func(prev interface{}) interface{} {
+				return prev.(int) + 1
+			}),
+			NewSequentialStream([]int{10, 20, 30}),
+			[]int{10, 21, 32},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Zip(tt.a, tt.b, sum)
+			assert.False(t, s.IsParallel())
+			var res []int
+			err := s.Collect(&res)
+			assert.NoError(t, err)
+			assertSliceEquals(t, tt.expect, res)
+		})
+	}
+
+	t.Run("test pairs", func(t *testing.T) {
+		var res []Pair
+		err := Zip(NewSequentialStream([]int{1, 2}), NewSequentialStream([]string{"a", "b"}), nil).Collect(&res)
+		assert.NoError(t, err)
+		assert.Equal(t, []Pair{{1, "a"}, {2, "b"}}, res)
+	})
+
+	t.Run("test panic", func(t *testing.T) {
+		var res []int
+		err := Zip(NewSequentialStream([]int{1, 2}), NewSequentialStream([]int{1, 0}), func(first, second interface{}) interface{} {
+			return first.(int) / second.(int)
+		}).Collect(&res)
+		var p *PanicError
+		if assert.True(t, errors.As(err, &p)) {
+			assert.Equal(t, "Zip", p.Op)
+			assert.Equal(t, 1, p.Index)
+		}
+	})
+}
+
+func TestUnzip(t *testing.T) {
+	t.Run("test error", func(t *testing.T) {
+		first, second := Unzip(testErrStream)
+		assert.Error(t, first.Err())
+		assert.Error(t, second.Err())
+	})
+
+	t.Run("test normal", func(t *testing.T) {
+		evaluated := 0
+		pairs := NewSequentialStream([]int{1, 2, 3}).Peek(func(val interface{}) {
+			evaluated++
+		}).Map(func(src interface{}) (dest interface{}) {
+			return Pair{First: src, Second: strconv.Itoa(src.(int))}
+		})
+		first, second := Unzip(pairs)
+		assert.Equal(t, 0, evaluated)
+		var ints []int
+		assert.NoError(t, first.Collect(&ints))
+		assert.Equal(t, []int{1, 2, 3}, ints)
+		var strs []string
+		assert.NoError(t, second.Collect(&strs))
+		assert.Equal(t, []string{"1", "2", "3"}, strs)
+		assert.Equal(t, 3, evaluated)
+	})
+
+	t.Run("test zipped", func(t *testing.T) {
+		first, second := Unzip(Zip(NewSequentialStream([]int{1, 2}), NewSequentialStream([]int{3, 4, 5}), nil))
+		var res []int
+		assert.NoError(t, Zip(second, first, nil).Map(func(src interface{}) (dest interface{}) {
+			pair := src.(Pair)
+			return pair.First.(int) - pair.Second.(int)
+		}).Collect(&res))
+		assert.Equal(t, []int{2, 2}, res)
+	})
+
+	t.Run("test not pair", func(t *testing.T) {
+		first, _ := Unzip(NewSequentialStream([]int{1}))
+		var res []int
+		assert.EqualError(t, first.Collect(&res), "unzip error, element is int but not Pair")
+	})
+}
+
 func Test_sequentialStream_Collect(t *testing.T) {
 	testStreamCollect(t, newSequentialStreamForTest)
 }
@@ -76,6 +182,14 @@ func Test_sequentialStream_Skip(t *testing.T) {
 	testStreamSkip(t, newSequentialStreamForTest)
 }
 
+func Test_sequentialStream_TakeWhile(t *testing.T) {
+	testStreamTakeWhile(t, newSequentialStreamForTest)
+}
+
+func Test_sequentialStream_DropWhile(t *testing.T) {
+	testStreamDropWhile(t, newSequentialStreamForTest)
+}
+
 func Test_sequentialStream_IsParallel(t *testing.T) {
 	assert.False(t, newSequentialStreamForTest([]*element{}).IsParallel())
 }
@@ -136,6 +250,11 @@ func Test_errStream_Reduce(t *testing.T) {
 	assert.Nil(t, s)
 }
 
+func Test_errStream_TakeWhile(t *testing.T) {
+	assert.Equal(t, testErrStream, testErrStream.TakeWhile(nil))
+	assert.Equal(t, testErrStream, testErrStream.DropWhile(nil))
+}
+
 func Test_errStream_Skip(t *testing.T) {
 	s := testErrStream.Skip(1)
 	assert.Same(t, testErrStream, s)
@@ -272,7 +391,7 @@ func Test_parallelStream_Map(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := &parallelStream{intSliceToElements(tt.elements)}
+			s := newParallelStreamForTest(intSliceToElements(tt.elements))
 			var dest []int
 			err := s.Map(func(src interface{}) (dest interface{}) {
 				return src.(int) * 2
@@ -299,6 +418,14 @@ func Test_parallelStream_Skip(t *testing.T) {
 	testStreamSkip(t, newParallelStreamForTest)
 }
 
+func Test_parallelStream_TakeWhile(t *testing.T) {
+	testStreamTakeWhile(t, newParallelStreamForTest)
+}
+
+func Test_parallelStream_DropWhile(t *testing.T) {
+	testStreamDropWhile(t, newParallelStreamForTest)
+}
+
 func Test_parallelStream_Sequential(t *testing.T) {
 	testStreamSequential(t, newParallelStreamForTest)
 }
@@ -744,6 +871,119 @@ func testStreamSkip(t *testing.T, stream func([]*element) Stream) {
 	}
 }
 
+func testStreamTakeWhile(t *testing.T, stream func([]*element) Stream) {
+	lessThan3 := func(val interface{}) (match bool) {
+		return val.(int) < 3
+	}
+	tests := []struct {
+		name     string
+		elements []int
+		expect   []int
+	}{
+		{"test empty", []int{}, []int{}},
+		{"test all match", []int{0, 1, 2}, []int{0, 1, 2}},
+		{"test none match", []int{3, 1, 2}, []int{}},
+		{"test prefix match", []int{1, 2, 3, 1, 2}, []int{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := stream(intSliceToElements(tt.elements))
+			parallel := s.IsParallel()
+			s = s.TakeWhile(lessThan3)
+			assert.Equal(t, parallel, s.IsParallel())
+			var res []int
+			err := s.Collect(&res)
+			assert.NoError(t, err)
+			assertSliceEquals(t, tt.expect, res)
+		})
+	}
+
+	t.Run("test encounter order", func(t *testing.T) {
+		ints := make([]int, 1000)
+		for i := range ints {
+			ints[i] = i
+		}
+		var res []int
+		err := stream(intSliceToElements(ints)).WithParallelism(4).TakeWhile(func(val interface{}) (match bool) {
+			return val.(int) != 777
+		}).Collect(&res)
+		assert.NoError(t, err)
+		assertSliceEquals(t, ints[:777], res)
+	})
+
+	t.Run("test stop early", func(t *testing.T) {
+		var tested int64
+		var res []int
+		err := stream(intSliceToElements([]int{1, 2, 3, 4, 5, 6, 7, 8})).WithParallelism(2).
+			TakeWhile(func(val interface{}) (match bool) {
+				atomic.AddInt64(&tested, 1)
+				return val.(int) < 3
+			}).Collect(&res)
+		assert.NoError(t, err)
+		assertSliceEquals(t, []int{1, 2}, res)
+		assert.LessOrEqual(t, atomic.LoadInt64(&tested), int64(4))
+	})
+
+	t.Run("test panic", func(t *testing.T) {
+		var res []int
+		err := stream(intSliceToElements([]int{1, 2, 3, 4, 5})).TakeWhile(func(val interface{}) (match bool) {
+			if val.(int) == 4 {
+				panic("boom")
+			}
+			return true
+		}).Collect(&res)
+		var p *PanicError
+		if assert.True(t, errors.As(err, &p)) {
+			assert.Equal(t, "TakeWhile", p.Op)
+			assert.Equal(t, 3, p.Index)
+		}
+	})
+}
+
+func testStreamDropWhile(t *testing.T, stream func([]*element) Stream) {
+	lessThan3 := func(val interface{}) (match bool) {
+		return val.(int) < 3
+	}
+	tests := []struct {
+		name     string
+		elements []int
+		expect   []int
+	}{
+		{"test empty", []int{}, []int{}},
+		{"test all match", []int{0, 1, 2}, []int{}},
+		{"test none match", []int{3, 1, 2}, []int{3, 1, 2}},
+		{"test prefix match", []int{1, 2, 3, 1, 2}, []int{3, 1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := stream(intSliceToElements(tt.elements))
+			parallel := s.IsParallel()
+			s = s.DropWhile(lessThan3)
+			assert.Equal(t, parallel, s.IsParallel())
+			var res []int
+			err := s.Collect(&res)
+			assert.NoError(t, err)
+			assertSliceEquals(t, tt.expect, res)
+		})
+	}
+
+	t.Run("test elements after mismatch not tested", func(t *testing.T) {
+		ints := make([]int, 1000)
+		for i := range ints {
+			ints[i] = i
+		}
+		var tested int64
+		var res []int
+		err := stream(intSliceToElements(ints)).WithParallelism(4).DropWhile(func(val interface{}) (match bool) {
+			atomic.AddInt64(&tested, 1)
+			return val.(int) < 10
+		}).Collect(&res)
+		assert.NoError(t, err)
+		assertSliceEquals(t, ints[10:], res)
+		assert.LessOrEqual(t, atomic.LoadInt64(&tested), int64(12))
+	})
+}
+
 func testStreamSequential(t *testing.T, stream func([]*element) Stream) {
 	assert.False(t, stream([]*element{}).Sequential().IsParallel())
 	assert.False(t, stream(intSliceToElements([]int{1})).Sequential().IsParallel())
@@ -761,11 +1001,11 @@ func intSliceToElements(src []int) (elements []*element) {
 }
 
 func newSequentialStreamForTest(elements []*element) Stream {
-	return &sequentialStream{elements}
+	return newSequentialStream(config{}, sliceStage(elements))
 }
 
 func newParallelStreamForTest(elements []*element) Stream {
-	return &parallelStream{elements}
+	return newParallelStream(config{}, sliceStage(elements))
 }
 
 func assertSliceEquals(t *testing.T, expect, actual []int) {
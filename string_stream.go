@@ -0,0 +1,347 @@
+package gostream
+
+import (
+	"context"
+	"strings"
+)
+
+// StringStream is a sequence of string-valued elements supporting sequential and parallel aggregate operations, it
+// has the operations of IntStream except the arithmetic ones, and the string-specific terminal operations.
+type StringStream interface {
+	BaseStream
+	// AllMatch returns whether all the elements of this stream match predicate, true is returned if the stream is
+	// empty. The elements are no longer tested once an element doesn't match.
+	AllMatch(predicate func(val string) (match bool)) (bool, error)
+	// AnyMatch returns whether any element of this stream matches predicate, false is returned if the stream is
+	// empty. The elements are no longer tested once an element matches.
+	AnyMatch(predicate func(val string) (match bool)) (bool, error)
+	// Chan returns a channel receiving the elements of this stream in encounter order, the channel is closed after
+	// all the elements were sent or an error occurred, and the error can be obtained by Err after the channel was
+	// closed.
+	Chan() <-chan string
+	// Chunk returns a Stream consisting of the slices of n consecutive elements of this stream in encounter order, the
+	// last slice has fewer elements if the length of this stream is not a multiple of n. The slices are of type
+	// []string. An error will occur if n is not positive.
+	Chunk(n int) Stream
+	// Collect returns a slice consisting of the elements of this stream.
+	Collect() ([]string, error)
+	// Concat returns the concatenation of the elements of this stream in encounter order.
+	Concat() (string, error)
+	// Distinct returns a stream consisting of the distinct elements of this stream.
+	Distinct() StringStream
+	// DropWhile returns a stream consisting of the remaining elements of this stream after dropping the longest prefix
+	// of elements matching predicate. The elements after the first mismatch are no longer tested.
+	DropWhile(predicate func(val string) (match bool)) StringStream
+	// Filter returns a stream consisting of the elements of this stream that match the given predicate.
+	Filter(predicate func(val string) (match bool)) StringStream
+	// FilterE is the Filter whose predicate may fail, the errors returned are handled according to the error
+	// mode of this stream.
+	FilterE(predicate func(val string) (match bool, err error)) StringStream
+	// FindAny returns a pointer describing some element of this stream, or a nil pointer if the stream is empty.
+	// A parallel stream may return any of its elements.
+	FindAny() (*string, error)
+	// FindFirst returns a pointer describing the first element of this stream, or a nil pointer if the stream is
+	// empty.
+	FindFirst() (*string, error)
+	// FlatMap returns a stream consisting of the results of replacing each element of this stream with the contents
+	// of a mapped stream produced by applying the provided mapper to each element.
+	FlatMap(mapper func(val string) StringStream) StringStream
+	// FlatMapE is the FlatMap whose mapper may fail, the errors returned are handled according to the error
+	// mode of this stream.
+	FlatMapE(mapper func(val string) (StringStream, error)) StringStream
+	// ForEach performs action for each element of this stream. A parallel stream performs the actions concurrently in
+	// no particular order, so action should be safe for concurrent use.
+	ForEach(action func(val string)) error
+	// ForEachOrdered performs action for each element of this stream one at a time in encounter order.
+	ForEachOrdered(action func(val string)) error
+	// Join returns the concatenation of the elements of this stream in encounter order, separated by sep.
+	Join(sep string) (string, error)
+	// Lengths returns an IntStream consisting of the lengths in bytes of the elements of this stream.
+	Lengths() IntStream
+	// Limit returns a stream consisting of the elements of this stream, truncated to be no longer than maxSize in
+	// length.
+	// An error will occur when maxSize is negative.
+	Limit(maxSize int) StringStream
+	// Map returns a stream consisting of results of applying the given mapper to the elements of this stream.
+	Map(mapper func(src string) (dest string)) StringStream
+	// MapE is the Map whose mapper may fail, the errors returned are handled according to the error mode of this
+	// stream.
+	MapE(mapper func(src string) (dest string, err error)) StringStream
+	// MapToFloat64 returns a Float64Stream consisting of the results of applying the given mapper to the elements of
+	// this stream.
+	MapToFloat64(mapper func(src string) (dest float64)) Float64Stream
+	// MapToInt returns an IntStream consisting of the results of applying the given mapper to the elements of
+	// this stream.
+	MapToInt(mapper func(src string) (dest int)) IntStream
+	// MapToInt64 returns an Int64Stream consisting of the results of applying the given mapper to the elements of
+	// this stream.
+	MapToInt64(mapper func(src string) (dest int64)) Int64Stream
+	// MapToObj returns an object-valued Stream consisting of the results of applying the given mapper to the elements
+	// of this stream.
+	MapToObj(mapper func(src string) (dest interface{})) Stream
+	// Max returns a pointer describing the lexicographically greatest element of this stream, or a nil pointer if the
+	// stream is empty.
+	Max() (*string, error)
+	// Min returns a pointer describing the lexicographically least element of this stream, or a nil pointer if the
+	// stream is empty.
+	Min() (*string, error)
+	// NoneMatch returns whether no element of this stream matches predicate, true is returned if the stream is
+	// empty. The elements are no longer tested once an element matches.
+	NoneMatch(predicate func(val string) (match bool)) (bool, error)
+	// Parallel returns an equivalent stream that is parallel.
+	Parallel() StringStream
+	// Peek returns a stream consisting of the elements of this stream, additionally performing action on each element.
+	// It's mainly to support debugging, a parallel stream performs the actions concurrently.
+	Peek(action func(val string)) StringStream
+	// Reduce performs a reduction on the elements of this stream, using an associative accumulation function, and
+	// returns a pointer describing the reduced value, if any, or a nil pointer if the stream is empty.
+	Reduce(op func(a, b string) (c string)) (*string, error)
+	// ReduceWithIdentity performs a reduction on the elements of this stream, using identity and an associative
+	// accumulation function, and returns the reduced value, which is identity if the stream is empty. identity should
+	// be the identity of op, since a parallel stream starts the reduction of each partition from it.
+	ReduceWithIdentity(identity string, op func(a, b string) (c string)) (string, error)
+	// Sequential returns an equivalent stream that is sequential.
+	Sequential() StringStream
+	// Skip returns a stream consisting of the remaining elements of this stream after discarding the first n elements
+	// of the stream. If this stream contains fewer than n elements then an empty stream will be returned.
+	// An error will occur if n is negative.
+	Skip(n int) StringStream
+	// Sorted returns a stream consisting of the elements of this stream in lexicographical order.
+	Sorted() StringStream
+	// SortedDescending returns a stream consisting of the elements of this stream in reverse lexicographical order.
+	SortedDescending() StringStream
+	// SortedWith returns a stream consisting of the elements of this stream, sorted according to less. The sort is
+	// stable, so the elements less considers equal keep their encounter order.
+	SortedWith(less func(a, b string) bool) StringStream
+	// TakeWhile returns a stream consisting of the longest prefix of elements of this stream matching predicate, the
+	// elements are no longer tested once an element doesn't match. A parallel stream tests the elements concurrently
+	// in batches, and keeps the encounter order.
+	TakeWhile(predicate func(val string) (match bool)) StringStream
+	// ToChan sends the elements of this stream to ch in encounter order, and closes ch after all the elements were
+	// sent or an error occurred.
+	ToChan(ch chan<- string) error
+	// Window returns a Stream consisting of the sliding windows of size consecutive elements of this stream, a window
+	// starts every step elements, so the windows overlap if step is less than size. Only the full windows are
+	// produced, and they are of type []string. An error will occur if size or step is not positive.
+	Window(size, step int) Stream
+	// WithContext returns an equivalent stream whose operations are cancelled once ctx is done, the error of ctx is
+	// reported by Err and the terminal operations.
+	WithContext(ctx context.Context) StringStream
+	// WithErrorMode returns an equivalent stream handling the errors returned by the functions supplied to MapE,
+	// FilterE and FlatMapE according to mode. An error will occur if mode is unknown.
+	WithErrorMode(mode ErrorMode) StringStream
+	// WithParallelism returns an equivalent stream whose parallel operations run on at most n goroutines, an error
+	// will occur if n is not positive. It has no effect on the sequential operations.
+	WithParallelism(n int) StringStream
+}
+
+// stringStream is the StringStream whose elements are pulled through the pipeline of a Stream, like TypedStream.
+type stringStream struct {
+	typedStream[string]
+}
+
+// NewSequentialStringStream returns a sequential ordered stream whose elements are the specified strings.
+func NewSequentialStringStream(strs []string) StringStream {
+	return newStringStream(NewSequentialStream(strs))
+}
+
+// NewParallelStringStream returns a parallel stream whose elements are the specified strings.
+func NewParallelStringStream(strs []string) StringStream {
+	return newStringStream(NewParallelStream(strs))
+}
+
+// ConcatStringStream creates a concatenated sequential stream whose elements are all the elements of the first stream
+// followed by all the elements of the second stream.
+func ConcatStringStream(a, b StringStream) StringStream {
+	return newStringStream(ConcatStream(untypedStringStream(a), untypedStringStream(b)))
+}
+
+// ZipString creates a sequential StringStream whose elements are the results of applying combiner to the elements of
+// a and b at the same position, the stream is as long as the shorter one of a and b.
+func ZipString(a, b StringStream, combiner func(first, second string) string) StringStream {
+	return newStringStream(Zip(untypedStringStream(a), untypedStringStream(b), func(first, second interface{}) interface{} {
+		return combiner(first.(string), second.(string))
+	}))
+}
+
+func newStringStream(s Stream) *stringStream {
+	return &stringStream{typedStream: typedStream[string]{s: s}}
+}
+
+// untypedStringStream returns a Stream consisting of the elements of s.
+func untypedStringStream(s StringStream) Stream {
+	if str, ok := s.(*stringStream); ok {
+		return str.s
+	}
+	return s.MapToObj(func(src string) (dest interface{}) {
+		return src
+	})
+}
+
+func (s *stringStream) with(stream Stream) StringStream {
+	return newStringStream(stream)
+}
+
+func (s *stringStream) Chunk(n int) Stream {
+	return typedSlices[string](s.s.Chunk(n))
+}
+
+func (s *stringStream) Concat() (string, error) {
+	return s.Join("")
+}
+
+func (s *stringStream) Distinct() StringStream {
+	return s.with(s.s.Distinct(func(obj interface{}) interface{} {
+		return obj
+	}, func(a, b interface{}) bool {
+		return a.(string) == b.(string)
+	}))
+}
+
+func (s *stringStream) DropWhile(predicate func(val string) (match bool)) StringStream {
+	return s.with(s.s.DropWhile(untypedPredicate(predicate)))
+}
+
+func (s *stringStream) Filter(predicate func(val string) (match bool)) StringStream {
+	return s.with(s.s.Filter(untypedPredicate(predicate)))
+}
+
+func (s *stringStream) FilterE(predicate func(val string) (match bool, err error)) StringStream {
+	return s.with(s.s.FilterE(func(val interface{}) (match bool, err error) {
+		return predicate(val.(string))
+	}))
+}
+
+func (s *stringStream) FlatMap(mapper func(val string) StringStream) StringStream {
+	return s.with(s.s.FlatMap(func(val interface{}) Stream {
+		return untypedStringStream(mapper(val.(string)))
+	}))
+}
+
+func (s *stringStream) FlatMapE(mapper func(val string) (StringStream, error)) StringStream {
+	return s.with(s.s.FlatMapE(func(val interface{}) (Stream, error) {
+		stream, err := mapper(val.(string))
+		if err != nil {
+			return nil, err
+		}
+		return untypedStringStream(stream), nil
+	}))
+}
+
+func (s *stringStream) Join(sep string) (string, error) {
+	strs, err := s.Collect()
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(strs, sep), nil
+}
+
+func (s *stringStream) Lengths() IntStream {
+	return s.MapToInt(func(src string) (dest int) {
+		return len(src)
+	})
+}
+
+func (s *stringStream) Limit(maxSize int) StringStream {
+	return s.with(s.s.Limit(maxSize))
+}
+
+func (s *stringStream) Map(mapper func(src string) (dest string)) StringStream {
+	return s.with(s.s.Map(func(src interface{}) (dest interface{}) {
+		return mapper(src.(string))
+	}))
+}
+
+func (s *stringStream) MapE(mapper func(src string) (dest string, err error)) StringStream {
+	return s.with(s.s.MapE(func(src interface{}) (dest interface{}, err error) {
+		return mapper(src.(string))
+	}))
+}
+
+func (s *stringStream) MapToObj(mapper func(src string) (dest interface{})) Stream {
+	return s.s.Map(func(src interface{}) (dest interface{}) {
+		return mapper(src.(string))
+	})
+}
+
+func (s *stringStream) Max() (*string, error) {
+	return s.Reduce(func(a, b string) (c string) {
+		if b > a {
+			return b
+		}
+		return a
+	})
+}
+
+func (s *stringStream) Min() (*string, error) {
+	return s.Reduce(func(a, b string) (c string) {
+		if b < a {
+			return b
+		}
+		return a
+	})
+}
+
+func (s *stringStream) Parallel() StringStream {
+	return s.with(s.s.Parallel())
+}
+
+func (s *stringStream) Peek(action func(val string)) StringStream {
+	return s.with(s.s.Peek(untypedAction(action)))
+}
+
+func (s *stringStream) ReduceWithIdentity(identity string, op func(a, b string) (c string)) (string, error) {
+	result, err := s.s.ReduceWithIdentity(identity, func(a, b interface{}) (c interface{}) {
+		return op(a.(string), b.(string))
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.(string), nil
+}
+
+func (s *stringStream) Sequential() StringStream {
+	return s.with(s.s.Sequential())
+}
+
+func (s *stringStream) Skip(n int) StringStream {
+	return s.with(s.s.Skip(n))
+}
+
+func (s *stringStream) Sorted() StringStream {
+	return s.SortedWith(func(a, b string) bool {
+		return a < b
+	})
+}
+
+func (s *stringStream) SortedDescending() StringStream {
+	return s.SortedWith(func(a, b string) bool {
+		return a > b
+	})
+}
+
+func (s *stringStream) SortedWith(less func(a, b string) bool) StringStream {
+	return s.with(s.s.Sorted(func(a, b interface{}) bool {
+		return less(a.(string), b.(string))
+	}))
+}
+
+func (s *stringStream) TakeWhile(predicate func(val string) (match bool)) StringStream {
+	return s.with(s.s.TakeWhile(untypedPredicate(predicate)))
+}
+
+func (s *stringStream) Window(size, step int) Stream {
+	return typedSlices[string](s.s.Window(size, step))
+}
+
+func (s *stringStream) WithContext(ctx context.Context) StringStream {
+	return s.with(s.s.WithContext(ctx))
+}
+
+func (s *stringStream) WithErrorMode(mode ErrorMode) StringStream {
+	return s.with(s.s.WithErrorMode(mode))
+}
+
+func (s *stringStream) WithParallelism(n int) StringStream {
+	return s.with(s.s.WithParallelism(n))
+}
@@ -0,0 +1,140 @@
+package gostream
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestStringStream(t *testing.T) {
+	words := []string{"pear", "apple", "fig", "apple", "banana"}
+	for _, newStream := range []func([]string) StringStream{NewSequentialStringStream, NewParallelStringStream} {
+		s := newStream(words)
+
+		joined, err := s.Join(", ")
+		assert.NoError(t, err)
+		assert.Equal(t, "pear, apple, fig, apple, banana", joined)
+
+		concatenated, err := s.Filter(func(val string) bool {
+			return len(val) > 4
+		}).Concat()
+		assert.NoError(t, err)
+		assert.Equal(t, "appleapplebanana", concatenated)
+
+		lengths, err := s.Lengths().Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []int{4, 5, 3, 5, 6}, lengths)
+
+		sorted, err := s.Distinct().Sorted().Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"apple", "banana", "fig", "pear"}, sorted)
+		sorted, err = s.Distinct().SortedDescending().Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"pear", "fig", "banana", "apple"}, sorted)
+		sorted, err = s.SortedWith(func(a, b string) bool {
+			return len(a) < len(b)
+		}).Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"fig", "pear", "apple", "apple", "banana"}, sorted)
+
+		initials, err := s.Map(func(src string) (dest string) {
+			return src[:1]
+		}).ReduceWithIdentity("", func(a, b string) (c string) {
+			return a + b
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "pafab", initials)
+		empty, err := newStream(nil).ReduceWithIdentity("-", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "-", empty)
+
+		upper, err := s.Map(strings.ToUpper).Limit(2).Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"PEAR", "APPLE"}, upper)
+
+		max, err := s.Max()
+		assert.NoError(t, err)
+		assert.Equal(t, "pear", *max)
+		min, err := s.Min()
+		assert.NoError(t, err)
+		assert.Equal(t, "apple", *min)
+
+		letters, err := s.Skip(2).TakeWhile(func(val string) bool {
+			return val != "banana"
+		}).FlatMap(func(val string) StringStream {
+			return NewSequentialStringStream(strings.Split(val, ""))
+		}).Join("")
+		assert.NoError(t, err)
+		assert.Equal(t, "figapple", letters)
+
+		var chunks [][]string
+		assert.NoError(t, s.Chunk(2).Collect(&chunks))
+		assert.Equal(t, [][]string{{"pear", "apple"}, {"fig", "apple"}, {"banana"}}, chunks)
+	}
+
+	t.Run("test empty", func(t *testing.T) {
+		s := NewParallelStringStream(nil)
+		joined, err := s.Join(",")
+		assert.NoError(t, err)
+		assert.Equal(t, "", joined)
+		max, err := s.Max()
+		assert.NoError(t, err)
+		assert.Nil(t, max)
+	})
+
+	t.Run("test concat and zip", func(t *testing.T) {
+		a := NewSequentialStringStream([]string{"a", "b"})
+		b := NewParallelStringStream([]string{"x", "y", "z"})
+		res, err := ConcatStringStream(a, b).Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b", "x", "y", "z"}, res)
+		res, err = ZipString(a, b, func(first, second string) string {
+			return first + second
+		}).Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ax", "by"}, res)
+	})
+
+	t.Run("test error", func(t *testing.T) {
+		e := errors.New("bad number")
+		_, err := NewSequentialStringStream([]string{"1", "x"}).MapE(func(src string) (string, error) {
+			if _, err := strconv.Atoi(src); err != nil {
+				return "", e
+			}
+			return src, nil
+		}).Concat()
+		assert.Equal(t, e, err)
+
+		_, err = testErrStream.MapToString(func(src interface{}) (dest string) {
+			return ""
+		}).Join(",")
+		assert.Error(t, err)
+		_, err = testErrIntStream.MapToString(strconv.Itoa).Collect()
+		assert.Error(t, err)
+	})
+}
+
+func TestMapToString(t *testing.T) {
+	for _, s := range []IntStream{NewSequentialIntStream([]int{1, 2, 3}), NewParallelIntStream([]int{1, 2, 3}),
+		IntRangeClosed(1, 3)} {
+		joined, err := s.MapToString(strconv.Itoa).Join("-")
+		assert.NoError(t, err)
+		assert.Equal(t, "1-2-3", joined)
+	}
+
+	for _, newStream := range []func([]*element) Stream{newSequentialStreamForTest, newParallelStreamForTest} {
+		res, err := newStream(intSliceToElements([]int{1, 2})).MapToString(func(src interface{}) (dest string) {
+			return strconv.Itoa(src.(int) * 10)
+		}).Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"10", "20"}, res)
+	}
+
+	res, err := NewSequentialTypedStream([]float64{1.5}).MapToString(func(src float64) (dest string) {
+		return strconv.FormatFloat(src, 'f', -1, 64)
+	}).Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1.5"}, res)
+}
@@ -0,0 +1,180 @@
+package gostream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+// ErrOverflow is wrapped by the error of SumChecked when the sum of the elements is out of the range of their type.
+var ErrOverflow = errors.New("overflow")
+
+// summation sums numbers without losing precision. The floating-point numbers are summed with Kahan-Neumaier
+// compensated summation, and the integers are summed into a 128-bit integer, which never overflows, so that the
+// partial summations of a parallel stream can be merged in any order. The zero value is the summation of no number.
+type summation[N Number] struct {
+	// sum and compensation are the compensated summation of the floating-point numbers, infinite reports whether an
+	// infinite number was summed.
+	sum          float64
+	compensation float64
+	infinite     bool
+
+	// hi and lo are the high and low 64 bits of the 128-bit two's complement sum of the integers.
+	hi int64
+	lo uint64
+}
+
+// summate returns the summation of numbers.
+func summate[N Number](numbers []N) *summation[N] {
+	s := &summation[N]{}
+	for _, n := range numbers {
+		s.add(n)
+	}
+	return s
+}
+
+// parallelSummate sums the chunks of numbers concurrently and merges the partial summations in encounter order.
+func parallelSummate[N Number](ctx context.Context, parallelism int, numbers []N) (*summation[N], error) {
+	chunks := numberChunks(numbers, parallelism)
+	partials := make([]*summation[N], len(chunks))
+	err := parallelEach(ctx, "Sum", parallelism, len(chunks), func(i int) {
+		partials[i] = summate(chunks[i])
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := &summation[N]{}
+	for _, partial := range partials {
+		result.merge(partial)
+	}
+	return result, nil
+}
+
+func (s *summation[N]) add(n N) {
+	if isFloating[N]() {
+		s.addFloat(float64(n))
+		return
+	}
+	if isSigned[N]() {
+		v := int64(n)
+		s.addWide(v>>63, uint64(v))
+	} else {
+		s.addWide(0, uint64(n))
+	}
+}
+
+func (s *summation[N]) addFloat(x float64) {
+	if math.IsInf(x, 0) {
+		s.infinite = true
+	}
+	t := s.sum + x
+	if math.IsInf(t, 0) || math.IsNaN(t) {
+		// the sum is no longer finite, the compensation is meaningless
+		s.sum = t
+		return
+	}
+	if math.Abs(s.sum) >= math.Abs(x) {
+		s.compensation += (s.sum - t) + x
+	} else {
+		s.compensation += (x - t) + s.sum
+	}
+	s.sum = t
+}
+
+func (s *summation[N]) addWide(hi int64, lo uint64) {
+	var carry uint64
+	s.lo, carry = bits.Add64(s.lo, lo, 0)
+	s.hi += hi + int64(carry)
+}
+
+// merge adds the numbers summed by other.
+func (s *summation[N]) merge(other *summation[N]) {
+	if isFloating[N]() {
+		s.addFloat(other.sum)
+		s.addFloat(other.compensation)
+		s.infinite = s.infinite || other.infinite
+		return
+	}
+	s.addWide(other.hi, other.lo)
+}
+
+// asFloat64 returns the sum as a float64, the sum of integers never overflows, and it's rounded to the nearest
+// float64 once.
+func (s *summation[N]) asFloat64() float64 {
+	if isFloating[N]() {
+		return s.sum + s.compensation
+	}
+	if s.hi == int64(s.lo)>>63 {
+		// the sum fits in an int64
+		return float64(int64(s.lo))
+	}
+	wide := new(big.Int).Lsh(big.NewInt(s.hi), 64)
+	wide.Add(wide, new(big.Int).SetUint64(s.lo))
+	f, _ := new(big.Float).SetInt(wide).Float64()
+	return f
+}
+
+// value returns the sum as N, the sum of integers wraps around on overflow like the + operator.
+func (s *summation[N]) value() N {
+	if isFloating[N]() {
+		return N(s.asFloat64())
+	}
+	if isSigned[N]() {
+		return N(int64(s.lo))
+	}
+	return N(s.lo)
+}
+
+// checked returns the sum as N, an error wrapping ErrOverflow is returned if the sum is out of the range of N.
+func (s *summation[N]) checked() (N, error) {
+	sum := s.value()
+	var overflow bool
+	if isFloating[N]() {
+		overflow = math.IsInf(float64(sum), 0) && !s.infinite
+	} else if isSigned[N]() {
+		overflow = s.hi != int64(s.lo)>>63 || int64(sum) != int64(s.lo)
+	} else {
+		overflow = s.hi != 0 || uint64(sum) != s.lo
+	}
+	if overflow {
+		return sum, fmt.Errorf("sum error, %w of %T", ErrOverflow, sum)
+	}
+	return sum, nil
+}
+
+// isFloating reports whether N is a floating-point type.
+func isFloating[N Number]() bool {
+	half := 0.5
+	return N(half) != 0
+}
+
+// isSigned reports whether N is a signed type.
+func isSigned[N Number]() bool {
+	var zero N
+	return zero-1 < zero
+}
+
+// numberChunks splits numbers into at most batchSize(parallelism) chunks of about the same length.
+func numberChunks[N Number](numbers []N, parallelism int) [][]N {
+	n := batchSize(parallelism)
+	if n > len(numbers) {
+		n = len(numbers)
+	}
+	chunks := make([][]N, n)
+	for i := range chunks {
+		chunks[i] = numbers[i*len(numbers)/n : (i+1)*len(numbers)/n]
+	}
+	return chunks
+}
+
+func sumNumbers[N Number](numbers []N) N {
+	return summate(numbers).value()
+}
+
+// averageNumbers returns the arithmetic mean of numbers, the sum of integers never overflows.
+func averageNumbers[N Number](numbers []N) float64 {
+	return summate(numbers).asFloat64() / float64(len(numbers))
+}
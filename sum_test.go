@@ -0,0 +1,159 @@
+package gostream
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestFloat64StreamSum(t *testing.T) {
+	tenths := make([]float64, 1000)
+	for i := range tenths {
+		tenths[i] = 0.1
+	}
+	tests := []struct {
+		name   string
+		floats []float64
+		expect float64
+	}{
+		{"test empty", nil, 0},
+		{"test cancellation", []float64{1, 1e100, 1, -1e100}, 2},
+		{"test tenths", tenths, 100},
+		{"test infinite", []float64{1, math.Inf(1), 1e308, 1e308}, math.Inf(1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, s := range []Float64Stream{
+				NewSequentialFloat64Stream(tt.floats),
+				NewParallelFloat64Stream(tt.floats),
+				NewParallelFloat64Stream(tt.floats).WithParallelism(3),
+			} {
+				sum, err := s.Sum()
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expect, sum)
+				stats, err := s.SummaryStatistics()
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expect, stats.Sum())
+			}
+		})
+	}
+
+	t.Run("test checked", func(t *testing.T) {
+		for _, s := range []Float64Stream{
+			NewSequentialFloat64Stream([]float64{math.MaxFloat64, math.MaxFloat64}),
+			NewParallelFloat64Stream([]float64{math.MaxFloat64, math.MaxFloat64}),
+		} {
+			sum, err := s.SumChecked()
+			assert.True(t, errors.Is(err, ErrOverflow))
+			assert.EqualError(t, err, "sum error, overflow of float64")
+			assert.True(t, math.IsInf(sum, 1))
+		}
+
+		sum, err := NewSequentialFloat64Stream([]float64{1, math.Inf(-1)}).SumChecked()
+		assert.NoError(t, err)
+		assert.True(t, math.IsInf(sum, -1))
+	})
+}
+
+func TestIntStreamSum(t *testing.T) {
+	t.Run("test wraparound", func(t *testing.T) {
+		for _, s := range []IntStream{
+			NewSequentialIntStream([]int{math.MaxInt, 1}),
+			NewParallelIntStream([]int{math.MaxInt, 1}),
+		} {
+			sum, err := s.Sum()
+			assert.NoError(t, err)
+			assert.Equal(t, math.MinInt, sum)
+
+			_, err = s.SumChecked()
+			assert.True(t, errors.Is(err, ErrOverflow))
+			assert.EqualError(t, err, "sum error, overflow of int")
+		}
+	})
+
+	t.Run("test intermediate overflow", func(t *testing.T) {
+		for _, s := range []IntStream{
+			NewSequentialIntStream([]int{math.MaxInt, 1, -1}),
+			NewParallelIntStream([]int{math.MaxInt, 1, -1}).WithParallelism(3),
+		} {
+			sum, err := s.SumChecked()
+			assert.NoError(t, err)
+			assert.Equal(t, math.MaxInt, sum)
+		}
+	})
+
+	t.Run("test average", func(t *testing.T) {
+		for _, s := range []IntStream{
+			NewSequentialIntStream([]int{math.MaxInt, math.MaxInt}),
+			NewParallelIntStream([]int{math.MaxInt, math.MaxInt}),
+		} {
+			average, err := s.Average()
+			assert.NoError(t, err)
+			assert.Equal(t, float64(math.MaxInt), *average)
+		}
+	})
+
+	t.Run("test negative average", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			ints   []int
+			expect float64
+		}{
+			{"test negative", []int{-1, -2}, -1.5},
+			{"test mixed signs", []int{-7, 3, -2}, -2},
+			{"test min", []int{math.MinInt, math.MinInt}, math.MinInt},
+			{"test below min", []int{math.MinInt, -1, math.MinInt, -1}, math.MinInt / 2},
+			{"test cancelled out", []int{math.MaxInt, math.MinInt, math.MaxInt, 5}, float64(math.MaxInt) / 4},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				for _, s := range []IntStream{NewSequentialIntStream(tt.ints), NewParallelIntStream(tt.ints).WithParallelism(2)} {
+					average, err := s.Average()
+					assert.NoError(t, err)
+					assert.Equal(t, tt.expect, *average)
+				}
+			})
+		}
+
+		average, err := IntRange(-5, 0).Average()
+		assert.NoError(t, err)
+		assert.Equal(t, -3.0, *average)
+		averages, err := NewSequentialIntStream([]int{-4, -2, 6}).MovingAverage(2).Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []float64{-3, 2}, averages)
+		unsigned, err := NewParallelNumberStream([]uint64{math.MaxUint64, math.MaxUint64 - 2}).Average()
+		assert.NoError(t, err)
+		assert.Equal(t, float64(math.MaxUint64), *unsigned)
+	})
+
+	t.Run("test parallel", func(t *testing.T) {
+		ints := make([]int, 10001)
+		for i := range ints {
+			ints[i] = (i - 5000) * 997
+		}
+		expect, err := NewSequentialIntStream(ints).SumChecked()
+		assert.NoError(t, err)
+		assert.Equal(t, 0, expect)
+		for _, parallelism := range []int{1, 3, 8} {
+			sum, err := NewParallelIntStream(ints).WithParallelism(parallelism).SumChecked()
+			assert.NoError(t, err)
+			assert.Equal(t, expect, sum)
+		}
+	})
+
+	t.Run("test other types", func(t *testing.T) {
+		_, err := NewSequentialNumberStream([]int8{100, 28}).SumChecked()
+		assert.EqualError(t, err, "sum error, overflow of int8")
+		sum, err := NewParallelNumberStream([]int8{-100, -28}).SumChecked()
+		assert.NoError(t, err)
+		assert.Equal(t, int8(-128), sum)
+		_, err = NewSequentialNumberStream([]uint{math.MaxUint, 1}).SumChecked()
+		assert.True(t, errors.Is(err, ErrOverflow))
+		_, err = NewParallelNumberStream([]uint8{0, 255, 1}).SumChecked()
+		assert.True(t, errors.Is(err, ErrOverflow))
+	})
+
+	_, err := testErrIntStream.SumChecked()
+	assert.Error(t, err)
+}
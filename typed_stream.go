@@ -0,0 +1,482 @@
+package gostream
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// TypedStream is a sequence of elements of type T supporting sequential and parallel aggregate operations, it's the
+// type-safe counterpart of Stream, so the functions supplied take and return T rather than interface{}.
+// The operations changing the element type are the functions Map, MapE, FlatMap and FlatMapE, since a method can't
+// have type parameters.
+type TypedStream[T any] interface {
+	BaseStream
+	// AllMatch returns whether all the elements of this stream match predicate, true is returned if the stream is
+	// empty. The elements are no longer tested once an element doesn't match.
+	AllMatch(predicate func(val T) (match bool)) (bool, error)
+	// AnyMatch returns whether any element of this stream matches predicate, false is returned if the stream is
+	// empty. The elements are no longer tested once an element matches.
+	AnyMatch(predicate func(val T) (match bool)) (bool, error)
+	// Chan returns a channel receiving the elements of this stream in encounter order. The elements are evaluated in a
+	// new goroutine, the channel is closed after all the elements were sent or an error occurred, and the error can
+	// be obtained by Err after the channel was closed.
+	Chan() <-chan T
+	// Collect returns a slice consisting of the elements of this stream.
+	Collect() ([]T, error)
+	// CollectWith performs a mutable reduction on the elements of this stream with collector, and returns the result
+	// of collector.
+	CollectWith(collector Collector) (interface{}, error)
+	// Distinct returns a stream consisting of the distinct elements of this stream.
+	// hashcode should return the hashcode of val, 2 equal elements should return the same hashcode.
+	// equals should return true if a and b are equal, otherwise should return false.
+	Distinct(hashcode func(val T) interface{}, equals func(a, b T) bool) TypedStream[T]
+	// DropWhile returns a stream consisting of the remaining elements of this stream after dropping the longest prefix
+	// of elements matching predicate. The elements after the first mismatch are no longer tested.
+	DropWhile(predicate func(val T) (match bool)) TypedStream[T]
+	// Filter returns a stream consisting of the elements of this stream that match the given predicate.
+	Filter(predicate func(val T) (match bool)) TypedStream[T]
+	// FilterE is the Filter whose predicate may fail, the errors returned are handled according to the error mode
+	// of this stream.
+	FilterE(predicate func(val T) (match bool, err error)) TypedStream[T]
+	// FindAny returns a pointer describing some element of this stream, or a nil pointer if the stream is empty.
+	// A parallel stream may return any of its elements.
+	FindAny() (*T, error)
+	// FindFirst returns a pointer describing the first element of this stream, or a nil pointer if the stream is
+	// empty. The upstream elements are no longer evaluated once the first element was found.
+	FindFirst() (*T, error)
+	// ForEach performs action for each element of this stream. A parallel stream performs the actions concurrently in
+	// no particular order, so action should be safe for concurrent use.
+	ForEach(action func(val T)) error
+	// ForEachOrdered performs action for each element of this stream one at a time in encounter order.
+	ForEachOrdered(action func(val T)) error
+	// FlatMap returns a stream consisting of the results of replacing each element of this stream with the contents of
+	// a mapped stream produced by applying mapper to each element.
+	FlatMap(mapper func(val T) TypedStream[T]) TypedStream[T]
+	// FlatMapE is the FlatMap whose mapper may fail, the errors returned are handled according to the error mode
+	// of this stream.
+	FlatMapE(mapper func(val T) (TypedStream[T], error)) TypedStream[T]
+	// Limit returns a stream consisting of elements of this stream, truncated to be no longer than maxSize in length.
+	// An error will occur when maxSize is negative.
+	Limit(maxSize int) TypedStream[T]
+	// Map returns a steam consisting of the results of applying mapper to the elements of this stream.
+	Map(mapper func(src T) (dest T)) TypedStream[T]
+	// MapE is the Map whose mapper may fail, the errors returned are handled according to the error mode of this
+	// stream.
+	MapE(mapper func(src T) (dest T, err error)) TypedStream[T]
+	// MapToFloat64 returns a Float64Stream consisting of the results of applying the given mapper to the elements of
+	// this stream.
+	MapToFloat64(mapper func(src T) (dest float64)) Float64Stream
+	// MapToInt returns an IntStream consisting of the results of applying the given mapper to the elements of
+	// this stream.
+	MapToInt(mapper func(src T) (dest int)) IntStream
+	// MapToInt64 returns an Int64Stream consisting of the results of applying the given mapper to the elements of
+	// this stream.
+	MapToInt64(mapper func(src T) (dest int64)) Int64Stream
+	// MapToString returns a StringStream consisting of the results of applying the given mapper to the elements of
+	// this stream.
+	MapToString(mapper func(src T) (dest string)) StringStream
+	// NoneMatch returns whether no element of this stream matches predicate, true is returned if the stream is
+	// empty. The elements are no longer tested once an element matches.
+	NoneMatch(predicate func(val T) (match bool)) (bool, error)
+	// Parallel returns an equivalent stream that is parallel.
+	Parallel() TypedStream[T]
+	// Peek returns a stream consisting of the elements of this stream, additionally performing action on each element
+	// as the elements are consumed from the resulting stream. It's mainly to support debugging.
+	Peek(action func(val T)) TypedStream[T]
+	// Reduce performs a reduction on the elements of this stream, using an associative accumulation function, and
+	// returns a pointer describing the reduced value, if any, or a nil pointer if the stream is empty.
+	Reduce(accumulator func(a, b T) (c T)) (*T, error)
+	// Sequential returns an equivalent stream that is sequential.
+	Sequential() TypedStream[T]
+	// Skip returns a stream consisting of the remaining elements of this stream after discarding the first n elements
+	// of the stream. If this stream contains fewer than n elements then an empty stream will be returned.
+	// An error will occur if n is negative.
+	Skip(n int) TypedStream[T]
+	// Sorted returns a stream consisting of the elements of this stream, sorted according to less.
+	Sorted(less func(a, b T) bool) TypedStream[T]
+	// SortedBy returns a stream consisting of the elements of this stream, sorted according to comparator.
+	SortedBy(comparator Comparator[T]) TypedStream[T]
+	// TakeWhile returns a stream consisting of the longest prefix of elements of this stream matching predicate, the
+	// upstream elements are no longer evaluated once an element doesn't match. A parallel stream tests the elements
+	// concurrently in batches, and keeps the encounter order.
+	TakeWhile(predicate func(val T) (match bool)) TypedStream[T]
+	// ToChan sends the elements of this stream to ch in encounter order, ch is closed after all the elements were sent
+	// or an error occurred.
+	ToChan(ch chan<- T) error
+	// Untyped returns the equivalent Stream, whose elements are of type T.
+	Untyped() Stream
+	// WithContext returns an equivalent stream whose stages are evaluated with ctx, no more work is scheduled once
+	// ctx is done, and the error of ctx is reported by Err and the terminal operations.
+	WithContext(ctx context.Context) TypedStream[T]
+	// WithErrorMode returns an equivalent stream handling the errors returned by the functions supplied to MapE,
+	// FilterE and FlatMapE according to mode. An error will occur if mode is unknown.
+	WithErrorMode(mode ErrorMode) TypedStream[T]
+	// WithParallelism returns an equivalent stream whose parallel operations run on at most n goroutines, an error
+	// will occur if n is not positive. It has no effect on the sequential operations.
+	WithParallelism(n int) TypedStream[T]
+}
+
+// typedStream adapts a Stream whose elements are all of type T to TypedStream, the pipeline of the Stream does the
+// work, and the elements are asserted back to T at the boundary.
+type typedStream[T any] struct {
+	s Stream
+	// mu guards chanErr, the type error of the latest ToChan, which is reported by Err since it isn't recorded by s.
+	mu      sync.Mutex
+	chanErr error
+}
+
+// NewSequentialTypedStream returns a sequential ordered stream whose elements are the specified elements.
+func NewSequentialTypedStream[T any](elements []T) TypedStream[T] {
+	return &typedStream[T]{s: NewSequentialStream(elements)}
+}
+
+// NewParallelTypedStream returns a parallel stream whose elements are the specified elements.
+func NewParallelTypedStream[T any](elements []T) TypedStream[T] {
+	return &typedStream[T]{s: NewParallelStream(elements)}
+}
+
+// Typed returns a TypedStream consisting of the elements of s, which should be of type T. The element of another type
+// fails with an error, which is handled according to the error mode of s.
+func Typed[T any](s Stream) TypedStream[T] {
+	return &typedStream[T]{s: s.MapE(func(src interface{}) (dest interface{}, err error) {
+		return assertType[T](src)
+	})}
+}
+
+// Map returns a stream consisting of the results of applying mapper to the elements of s.
+func Map[T, R any](s TypedStream[T], mapper func(src T) (dest R)) TypedStream[R] {
+	return &typedStream[R]{s: s.Untyped().Map(func(src interface{}) (dest interface{}) {
+		return mapper(as[T](src))
+	})}
+}
+
+// MapE is the Map whose mapper may fail, the errors returned are handled according to the error mode of s.
+func MapE[T, R any](s TypedStream[T], mapper func(src T) (dest R, err error)) TypedStream[R] {
+	return &typedStream[R]{s: s.Untyped().MapE(func(src interface{}) (dest interface{}, err error) {
+		return mapper(as[T](src))
+	})}
+}
+
+// Chunk returns a stream consisting of the slices of n consecutive elements of s in encounter order, the last slice has
+// fewer elements if the length of s is not a multiple of n. An error will occur if n is not positive.
+func Chunk[T any](s TypedStream[T], n int) TypedStream[[]T] {
+	return &typedStream[[]T]{s: typedSlices[T](s.Untyped().Chunk(n))}
+}
+
+// Window returns a stream consisting of the sliding windows of size consecutive elements of s, a window starts every
+// step elements, so the windows overlap if step is less than size. Only the full windows are produced.
+// An error will occur if size or step is not positive.
+func Window[T any](s TypedStream[T], size, step int) TypedStream[[]T] {
+	return &typedStream[[]T]{s: typedSlices[T](s.Untyped().Window(size, step))}
+}
+
+// FlatMap returns a stream consisting of the results of replacing each element of s with the contents of a mapped
+// stream produced by applying mapper to each element.
+func FlatMap[T, R any](s TypedStream[T], mapper func(val T) TypedStream[R]) TypedStream[R] {
+	return &typedStream[R]{s: s.Untyped().FlatMap(func(val interface{}) Stream {
+		return mapper(as[T](val)).Untyped()
+	})}
+}
+
+// FlatMapE is the FlatMap whose mapper may fail, the errors returned are handled according to the error mode of s.
+func FlatMapE[T, R any](s TypedStream[T], mapper func(val T) (TypedStream[R], error)) TypedStream[R] {
+	return &typedStream[R]{s: s.Untyped().FlatMapE(func(val interface{}) (Stream, error) {
+		stream, err := mapper(as[T](val))
+		if err != nil {
+			return nil, err
+		}
+		return stream.Untyped(), nil
+	})}
+}
+
+func (t *typedStream[T]) with(s Stream) TypedStream[T] {
+	return &typedStream[T]{s: s}
+}
+
+func (t *typedStream[T]) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.chanErr != nil {
+		return t.chanErr
+	}
+	return t.s.Err()
+}
+
+func (t *typedStream[T]) IsParallel() bool {
+	return t.s.IsParallel()
+}
+
+func (t *typedStream[T]) AllMatch(predicate func(val T) (match bool)) (bool, error) {
+	return t.s.AllMatch(untypedPredicate(predicate))
+}
+
+func (t *typedStream[T]) AnyMatch(predicate func(val T) (match bool)) (bool, error) {
+	return t.s.AnyMatch(untypedPredicate(predicate))
+}
+
+func (t *typedStream[T]) Chan() <-chan T {
+	ch := make(chan T)
+	go t.ToChan(ch)
+	return ch
+}
+
+func (t *typedStream[T]) Collect() ([]T, error) {
+	result, err := t.s.CollectWith(toTypedSlice[T]())
+	if err != nil {
+		return nil, err
+	}
+	return result.([]T), nil
+}
+
+func (t *typedStream[T]) CollectWith(collector Collector) (interface{}, error) {
+	return t.s.CollectWith(collector)
+}
+
+func (t *typedStream[T]) Distinct(hashcode func(val T) interface{}, equals func(a, b T) bool) TypedStream[T] {
+	return t.with(t.s.Distinct(func(obj interface{}) interface{} {
+		return hashcode(as[T](obj))
+	}, func(a, b interface{}) bool {
+		return equals(as[T](a), as[T](b))
+	}))
+}
+
+func (t *typedStream[T]) DropWhile(predicate func(val T) (match bool)) TypedStream[T] {
+	return t.with(t.s.DropWhile(untypedPredicate(predicate)))
+}
+
+func (t *typedStream[T]) Filter(predicate func(val T) (match bool)) TypedStream[T] {
+	return t.with(t.s.Filter(untypedPredicate(predicate)))
+}
+
+func (t *typedStream[T]) FilterE(predicate func(val T) (match bool, err error)) TypedStream[T] {
+	return t.with(t.s.FilterE(func(val interface{}) (match bool, err error) {
+		return predicate(as[T](val))
+	}))
+}
+
+func (t *typedStream[T]) FindAny() (*T, error) {
+	found, err := t.boxed().FindAny()
+	if found == nil {
+		return nil, err
+	}
+	return found.(*T), err
+}
+
+func (t *typedStream[T]) FindFirst() (*T, error) {
+	found, err := t.boxed().FindFirst()
+	if found == nil {
+		return nil, err
+	}
+	return found.(*T), err
+}
+
+func (t *typedStream[T]) FlatMap(mapper func(val T) TypedStream[T]) TypedStream[T] {
+	return FlatMap(TypedStream[T](t), mapper)
+}
+
+func (t *typedStream[T]) FlatMapE(mapper func(val T) (TypedStream[T], error)) TypedStream[T] {
+	return FlatMapE(TypedStream[T](t), mapper)
+}
+
+func (t *typedStream[T]) ForEach(action func(val T)) error {
+	return t.s.ForEach(untypedAction(action))
+}
+
+func (t *typedStream[T]) ForEachOrdered(action func(val T)) error {
+	return t.s.ForEachOrdered(untypedAction(action))
+}
+
+func (t *typedStream[T]) Limit(maxSize int) TypedStream[T] {
+	return t.with(t.s.Limit(maxSize))
+}
+
+func (t *typedStream[T]) Map(mapper func(src T) (dest T)) TypedStream[T] {
+	return Map(TypedStream[T](t), mapper)
+}
+
+func (t *typedStream[T]) MapE(mapper func(src T) (dest T, err error)) TypedStream[T] {
+	return MapE(TypedStream[T](t), mapper)
+}
+
+func (t *typedStream[T]) MapToFloat64(mapper func(src T) (dest float64)) Float64Stream {
+	return t.s.MapToFloat64(func(src interface{}) (dest float64) {
+		return mapper(as[T](src))
+	})
+}
+
+func (t *typedStream[T]) MapToInt(mapper func(src T) (dest int)) IntStream {
+	return t.s.MapToInt(func(src interface{}) (dest int) {
+		return mapper(as[T](src))
+	})
+}
+
+func (t *typedStream[T]) MapToInt64(mapper func(src T) (dest int64)) Int64Stream {
+	return t.s.MapToInt64(func(src interface{}) (dest int64) {
+		return mapper(as[T](src))
+	})
+}
+
+func (t *typedStream[T]) MapToString(mapper func(src T) (dest string)) StringStream {
+	return t.s.MapToString(func(src interface{}) (dest string) {
+		return mapper(as[T](src))
+	})
+}
+
+func (t *typedStream[T]) NoneMatch(predicate func(val T) (match bool)) (bool, error) {
+	return t.s.NoneMatch(untypedPredicate(predicate))
+}
+
+func (t *typedStream[T]) Parallel() TypedStream[T] {
+	return t.with(t.s.Parallel())
+}
+
+func (t *typedStream[T]) Peek(action func(val T)) TypedStream[T] {
+	return t.with(t.s.Peek(untypedAction(action)))
+}
+
+func (t *typedStream[T]) Reduce(accumulator func(a, b T) (c T)) (*T, error) {
+	reduced, err := t.boxed().Reduce(func(a, b interface{}) (c interface{}) {
+		result := accumulator(*a.(*T), *b.(*T))
+		return &result
+	})
+	if reduced == nil {
+		return nil, err
+	}
+	return reduced.(*T), err
+}
+
+func (t *typedStream[T]) Sequential() TypedStream[T] {
+	return t.with(t.s.Sequential())
+}
+
+func (t *typedStream[T]) Skip(n int) TypedStream[T] {
+	return t.with(t.s.Skip(n))
+}
+
+func (t *typedStream[T]) Sorted(less func(a, b T) bool) TypedStream[T] {
+	return t.with(t.s.Sorted(func(a, b interface{}) bool {
+		return less(as[T](a), as[T](b))
+	}))
+}
+
+func (t *typedStream[T]) SortedBy(comparator Comparator[T]) TypedStream[T] {
+	return t.Sorted(comparator.Less)
+}
+
+func (t *typedStream[T]) TakeWhile(predicate func(val T) (match bool)) TypedStream[T] {
+	return t.with(t.s.TakeWhile(untypedPredicate(predicate)))
+}
+
+func (t *typedStream[T]) ToChan(ch chan<- T) error {
+	defer close(ch)
+	var typeErr error
+	for val := range t.s.Chan() {
+		if typeErr != nil {
+			// the remaining elements are drained, so that the goroutine sending them exits
+			continue
+		}
+		var typed T
+		if typed, typeErr = assertType[T](val); typeErr == nil {
+			ch <- typed
+		}
+	}
+	t.mu.Lock()
+	t.chanErr = typeErr
+	t.mu.Unlock()
+	if typeErr != nil {
+		return typeErr
+	}
+	return t.s.Err()
+}
+
+func (t *typedStream[T]) Untyped() Stream {
+	return t.s
+}
+
+func (t *typedStream[T]) WithContext(ctx context.Context) TypedStream[T] {
+	return t.with(t.s.WithContext(ctx))
+}
+
+func (t *typedStream[T]) WithErrorMode(mode ErrorMode) TypedStream[T] {
+	return t.with(t.s.WithErrorMode(mode))
+}
+
+func (t *typedStream[T]) WithParallelism(n int) TypedStream[T] {
+	return t.with(t.s.WithParallelism(n))
+}
+
+// boxed returns a Stream consisting of the pointers to the elements, so that a nil element isn't mistaken for the
+// absence of an element.
+func (t *typedStream[T]) boxed() Stream {
+	return t.s.Map(func(src interface{}) (dest interface{}) {
+		val := as[T](src)
+		return &val
+	})
+}
+
+// toTypedSlice returns a Collector that accumulates the elements into a []T in encounter order.
+func toTypedSlice[T any]() Collector {
+	return NewCollector(
+		func() interface{} {
+			return make([]T, 0)
+		},
+		func(container, element interface{}) interface{} {
+			return append(container.([]T), as[T](element))
+		},
+		func(a, b interface{}) interface{} {
+			return append(a.([]T), b.([]T)...)
+		},
+		nil,
+	)
+}
+
+// typedSlices converts the slices of type []interface{} produced by s, such as the chunks, into []T.
+func typedSlices[T any](s Stream) Stream {
+	return s.Map(func(src interface{}) (dest interface{}) {
+		slice := src.([]interface{})
+		typed := make([]T, len(slice))
+		for i, val := range slice {
+			typed[i] = as[T](val)
+		}
+		return typed
+	})
+}
+
+func untypedAction[T any](action func(val T)) func(val interface{}) {
+	return func(val interface{}) {
+		action(as[T](val))
+	}
+}
+
+// untypedPredicate adapts predicate to the predicate of Stream.
+func untypedPredicate[T any](predicate func(val T) (match bool)) func(val interface{}) (match bool) {
+	return func(val interface{}) (match bool) {
+		return predicate(as[T](val))
+	}
+}
+
+// as returns val as a T for the functions applied by the underlying stream. The type error is panicked if val isn't a
+// T, so that it's reported by the stream as the *PanicError of the operation rather than taken as the zero value.
+func as[T any](val interface{}) T {
+	t, err := assertType[T](val)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// assertType returns val as a T, an error is returned if val isn't a T. nil is taken as the zero value only if T is an
+// interface, since a nil pointer, slice or map element is stored as a typed nil.
+func assertType[T any](val interface{}) (T, error) {
+	if t, ok := val.(T); ok {
+		return t, nil
+	}
+	var zero T
+	typ := reflect.TypeOf(&zero).Elem()
+	if val == nil && typ.Kind() == reflect.Interface {
+		return zero, nil
+	}
+	return zero, fmt.Errorf("type error, element is %T but not %v", val, typ)
+}
@@ -0,0 +1,194 @@
+package gostream
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+type typedWidget struct {
+	name   string
+	weight int
+}
+
+func TestTypedStream(t *testing.T) {
+	widgets := []*typedWidget{{"a", 3}, {"b", 1}, {"c", 2}, {"d", 4}}
+	for _, newStream := range []func([]*typedWidget) TypedStream[*typedWidget]{
+		NewSequentialTypedStream[*typedWidget],
+		NewParallelTypedStream[*typedWidget],
+	} {
+		t.Run("test map and filter", func(t *testing.T) {
+			s := newStream(widgets).Filter(func(val *typedWidget) (match bool) {
+				return val.weight > 1
+			})
+			names, err := Map(s, func(src *typedWidget) (dest string) {
+				return src.name
+			}).Collect()
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"a", "c", "d"}, names)
+		})
+
+		t.Run("test take and drop while", func(t *testing.T) {
+			heavy := func(val *typedWidget) (match bool) {
+				return val.weight > 1
+			}
+			res, err := newStream(widgets).TakeWhile(heavy).Collect()
+			assert.NoError(t, err)
+			assert.Equal(t, widgets[:1], res)
+			res, err = newStream(widgets).DropWhile(heavy).Collect()
+			assert.NoError(t, err)
+			assert.Equal(t, widgets[1:], res)
+		})
+
+		t.Run("test sorted and limit", func(t *testing.T) {
+			res, err := newStream(widgets).Sorted(func(a, b *typedWidget) bool {
+				return a.weight < b.weight
+			}).Skip(1).Limit(2).Collect()
+			assert.NoError(t, err)
+			assert.Equal(t, []*typedWidget{widgets[2], widgets[0]}, res)
+		})
+
+		t.Run("test reduce", func(t *testing.T) {
+			weights := Map(newStream(widgets), func(src *typedWidget) (dest int) {
+				return src.weight
+			})
+			sum, err := weights.Reduce(func(a, b int) (c int) {
+				return a + b
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, 10, *sum)
+			sum, err = weights.Limit(0).Reduce(func(a, b int) (c int) {
+				return a + b
+			})
+			assert.NoError(t, err)
+			assert.Nil(t, sum)
+			max, err := weights.MapToInt(func(src int) (dest int) {
+				return src
+			}).Max()
+			assert.NoError(t, err)
+			assert.Equal(t, 4, *max)
+		})
+
+		t.Run("test flat map", func(t *testing.T) {
+			res, err := FlatMap(newStream(widgets).Limit(2), func(val *typedWidget) TypedStream[string] {
+				return NewSequentialTypedStream([]string{val.name, strconv.Itoa(val.weight)})
+			}).Collect()
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"a", "3", "b", "1"}, res)
+		})
+
+		t.Run("test match and find", func(t *testing.T) {
+			match, err := newStream(widgets).AnyMatch(func(val *typedWidget) (match bool) {
+				return val.name == "c"
+			})
+			assert.NoError(t, err)
+			assert.True(t, match)
+			first, err := newStream(widgets).FindFirst()
+			assert.NoError(t, err)
+			assert.Equal(t, widgets[0], *first)
+			first, err = newStream(nil).FindFirst()
+			assert.NoError(t, err)
+			assert.Nil(t, first)
+		})
+
+		t.Run("test errors", func(t *testing.T) {
+			failed := errors.New("failed")
+			_, err := MapE(newStream(widgets), func(src *typedWidget) (dest int, err error) {
+				return 0, failed
+			}).Collect()
+			assert.Equal(t, failed, err)
+			_, err = newStream(widgets).Limit(-1).Collect()
+			assert.Error(t, err)
+		})
+
+		t.Run("test chan", func(t *testing.T) {
+			var res []*typedWidget
+			for w := range newStream(widgets).Chan() {
+				res = append(res, w)
+			}
+			assert.Equal(t, widgets, res)
+		})
+	}
+
+	t.Run("test nil elements", func(t *testing.T) {
+		failed := errors.New("failed")
+		s := NewSequentialTypedStream([]error{nil, failed})
+		res, err := s.Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []error{nil, failed}, res)
+		first, err := s.FindFirst()
+		assert.NoError(t, err)
+		assert.Nil(t, *first)
+		var sent []error
+		for e := range s.Chan() {
+			sent = append(sent, e)
+		}
+		assert.Equal(t, []error{nil, failed}, sent)
+	})
+
+	t.Run("test untyped adapter", func(t *testing.T) {
+		s := NewSequentialStream([]int{1, 2, 3})
+		res, err := Typed[int](s).Map(func(src int) (dest int) {
+			return src * 2
+		}).Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []int{2, 4, 6}, res)
+
+		var dest []string
+		assert.NoError(t, NewSequentialTypedStream([]string{"x", "y"}).Untyped().Collect(&dest))
+		assert.Equal(t, []string{"x", "y"}, dest)
+
+		_, err = Typed[string](s).Collect()
+		assert.EqualError(t, err, "type error, element is int but not string")
+		res, err = Typed[int](NewSequentialStream([]interface{}{1, "2", 3}).WithErrorMode(CollectAllErrors)).Collect()
+		var m *MultiError
+		assert.True(t, errors.As(err, &m))
+		assert.Nil(t, res)
+	})
+
+	t.Run("test type mismatch", func(t *testing.T) {
+		// the elements are not produced by the typed functions, so they may be of another type
+		s := &typedStream[int]{s: NewSequentialStream([]interface{}{1, "2", 3})}
+		_, err := s.Collect()
+		var p *PanicError
+		if assert.True(t, errors.As(err, &p)) {
+			assert.EqualError(t, p.Unwrap(), "type error, element is string but not int")
+		}
+		_, err = s.Filter(func(val int) (match bool) {
+			return val > 0
+		}).Collect()
+		assert.True(t, errors.As(err, &p))
+
+		var sent []int
+		for val := range s.Chan() {
+			sent = append(sent, val)
+		}
+		assert.Equal(t, []int{1}, sent)
+		assert.EqualError(t, s.Err(), "type error, element is string but not int")
+
+		_, err = Typed[*typedWidget](NewSequentialStream([]interface{}{nil})).Collect()
+		assert.EqualError(t, err, "type error, element is <nil> but not *gostream.typedWidget")
+		res, err := Typed[error](NewSequentialStream([]interface{}{nil})).Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []error{nil}, res)
+	})
+
+	t.Run("test context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := NewParallelTypedStream([]int{1, 2}).WithContext(ctx).Collect()
+		assert.Equal(t, context.Canceled, err)
+	})
+
+	t.Run("test panic", func(t *testing.T) {
+		_, err := Map(NewSequentialTypedStream([]int{1, 2}), func(src int) (dest int) {
+			panic("boom")
+		}).Collect()
+		var p *PanicError
+		if assert.True(t, errors.As(err, &p)) {
+			assert.Equal(t, "Map", p.Op)
+		}
+	})
+}
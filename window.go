@@ -0,0 +1,148 @@
+package gostream
+
+import "fmt"
+
+func (s *sequentialStream) Chunk(n int) Stream {
+	if n <= 0 {
+		return &errStream{err: fmt.Errorf("chunk error, n is not positive: %d", n)}
+	}
+	if s.tail == nil {
+		return s
+	}
+	return newSequentialStream(s.config, windowStage(s.tail, n, n, true))
+}
+
+func (s *sequentialStream) Window(size, step int) Stream {
+	if err := checkWindow(size, step); err != nil {
+		return &errStream{err: err}
+	}
+	if s.tail == nil {
+		return s
+	}
+	return newSequentialStream(s.config, windowStage(s.tail, size, step, false))
+}
+
+// Chunk of a parallel stream groups the elements produced by the upstream stages in encounter order, so a chunk never
+// mixes elements from different parts of the stream.
+func (p *parallelStream) Chunk(n int) Stream {
+	if n <= 0 {
+		return &errStream{err: fmt.Errorf("chunk error, n is not positive: %d", n), parallel: true}
+	}
+	if p.tail == nil {
+		return p
+	}
+	return newParallelStream(p.config, windowStage(p.tail, n, n, true))
+}
+
+func (p *parallelStream) Window(size, step int) Stream {
+	if err := checkWindow(size, step); err != nil {
+		return &errStream{err: err, parallel: true}
+	}
+	if p.tail == nil {
+		return p
+	}
+	return newParallelStream(p.config, windowStage(p.tail, size, step, false))
+}
+
+func (e *errStream) Chunk(int) Stream {
+	return e
+}
+
+func (e *errStream) Window(int, int) Stream {
+	return e
+}
+
+func (s *sequentialNumberStream[N]) Chunk(n int) Stream {
+	if n <= 0 {
+		return &errStream{err: fmt.Errorf("chunk error, n is not positive: %d", n)}
+	}
+	return newSequentialStream(s.config, sliceStage(windowNumbers(s.elements, n, n, true)))
+}
+
+func (s *sequentialNumberStream[N]) MovingAverage(size int) Float64Stream {
+	if size <= 0 {
+		return &errFloat64Stream{err: fmt.Errorf("moving average error, size is not positive: %d", size)}
+	}
+	return &sequentialFloat64Stream{elements: movingAverage(s.elements, size), config: s.config}
+}
+
+func (s *sequentialNumberStream[N]) Window(size, step int) Stream {
+	if err := checkWindow(size, step); err != nil {
+		return &errStream{err: err}
+	}
+	return newSequentialStream(s.config, sliceStage(windowNumbers(s.elements, size, step, false)))
+}
+
+func (p *parallelNumberStream[N]) Chunk(n int) Stream {
+	if n <= 0 {
+		return &errStream{err: fmt.Errorf("chunk error, n is not positive: %d", n), parallel: true}
+	}
+	return newParallelStream(p.config, sliceStage(windowNumbers(p.elements, n, n, true)))
+}
+
+func (p *parallelNumberStream[N]) MovingAverage(size int) Float64Stream {
+	if size <= 0 {
+		return &errFloat64Stream{err: fmt.Errorf("moving average error, size is not positive: %d", size), parallel: true}
+	}
+	return &parallelFloat64Stream{elements: movingAverage(p.elements, size), config: p.config}
+}
+
+func (p *parallelNumberStream[N]) Window(size, step int) Stream {
+	if err := checkWindow(size, step); err != nil {
+		return &errStream{err: err, parallel: true}
+	}
+	return newParallelStream(p.config, sliceStage(windowNumbers(p.elements, size, step, false)))
+}
+
+func (e *errNumberStream[N]) Chunk(int) Stream {
+	return &errStream{err: e.err, parallel: e.parallel}
+}
+
+func (e *errNumberStream[N]) MovingAverage(int) Float64Stream {
+	return &errFloat64Stream{err: e.err, parallel: e.parallel}
+}
+
+func (e *errNumberStream[N]) Window(int, int) Stream {
+	return &errStream{err: e.err, parallel: e.parallel}
+}
+
+func checkWindow(size, step int) error {
+	if size <= 0 {
+		return fmt.Errorf("window error, size is not positive: %d", size)
+	}
+	if step <= 0 {
+		return fmt.Errorf("window error, step is not positive: %d", step)
+	}
+	return nil
+}
+
+// windowNumbers returns the elements of the windows of size numbers starting every step numbers, the trailing window
+// with fewer numbers is kept only if partial is true. Each window is a copy, so that the windows never share memory.
+func windowNumbers[N Number](numbers []N, size, step int, partial bool) []*element {
+	windows := make([]*element, 0)
+	for start := 0; start < len(numbers); start += step {
+		end := start + size
+		if end > len(numbers) {
+			if !partial {
+				break
+			}
+			end = len(numbers)
+		}
+		window := make([]N, end-start)
+		copy(window, numbers[start:end])
+		windows = append(windows, newElement(window))
+	}
+	return windows
+}
+
+// movingAverage returns the arithmetic means of the windows of size consecutive numbers.
+func movingAverage[N Number](numbers []N, size int) []float64 {
+	if len(numbers) < size {
+		return nil
+	}
+	averages := make([]float64, len(numbers)-size+1)
+	for i := range averages {
+		averages[i] = averageNumbers(numbers[i : i+size])
+	}
+	return averages
+}
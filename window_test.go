@@ -0,0 +1,173 @@
+package gostream
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestStreamChunk(t *testing.T) {
+	tests := []struct {
+		name   string
+		ints   []int
+		n      int
+		expect []interface{}
+	}{
+		{"test empty", nil, 2, []interface{}{}},
+		{"test exact", []int{1, 2, 3, 4}, 2, []interface{}{[]interface{}{1, 2}, []interface{}{3, 4}}},
+		{"test partial", []int{1, 2, 3, 4, 5}, 2, []interface{}{[]interface{}{1, 2}, []interface{}{3, 4}, []interface{}{5}}},
+		{"test larger than stream", []int{1, 2}, 5, []interface{}{[]interface{}{1, 2}}},
+	}
+	for _, newStream := range []func([]*element) Stream{newSequentialStreamForTest, newParallelStreamForTest} {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				s := newStream(intSliceToElements(tt.ints))
+				parallel := s.IsParallel()
+				s = s.Chunk(tt.n)
+				assert.Equal(t, parallel, s.IsParallel())
+				res, err := s.CollectWith(ToSlice())
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expect, res)
+			})
+		}
+
+		t.Run("test n is not positive", func(t *testing.T) {
+			assert.EqualError(t, newStream(nil).Chunk(0).Err(), "chunk error, n is not positive: 0")
+		})
+	}
+
+	t.Run("test parallel boundaries", func(t *testing.T) {
+		ints := make([]int, 1000)
+		for i := range ints {
+			ints[i] = i
+		}
+		chunks, err := NewParallelStream(ints).WithParallelism(4).Map(func(src interface{}) (dest interface{}) {
+			return src.(int) * 2
+		}).Chunk(7).CollectWith(ToSlice())
+		assert.NoError(t, err)
+		assert.Len(t, chunks, 143)
+		for i, chunk := range chunks.([]interface{}) {
+			for j, val := range chunk.([]interface{}) {
+				assert.Equal(t, (i*7+j)*2, val)
+			}
+		}
+	})
+
+	t.Run("test infinite", func(t *testing.T) {
+		res, err := Iterate(0, nil, func(prev interface{}) interface{} {
+			return prev.(int) + 1
+		}).Chunk(3).Limit(2).CollectWith(ToSlice())
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{[]interface{}{0, 1, 2}, []interface{}{3, 4, 5}}, res)
+	})
+
+	assert.Equal(t, testErrStream, testErrStream.Chunk(1))
+	assert.Equal(t, testErrStream, testErrStream.Window(1, 1))
+}
+
+func TestStreamWindow(t *testing.T) {
+	tests := []struct {
+		name       string
+		ints       []int
+		size, step int
+		expect     []interface{}
+	}{
+		{"test empty", nil, 2, 1, []interface{}{}},
+		{"test shorter than size", []int{1, 2}, 3, 1, []interface{}{}},
+		{
+			"test sliding",
+			[]int{1, 2, 3, 4},
+			2, 1,
+			[]interface{}{[]interface{}{1, 2}, []interface{}{2, 3}, []interface{}{3, 4}},
+		},
+		{
+			"test overlapping",
+			[]int{1, 2, 3, 4, 5, 6},
+			3, 2,
+			[]interface{}{[]interface{}{1, 2, 3}, []interface{}{3, 4, 5}},
+		},
+		{
+			"test tumbling",
+			[]int{1, 2, 3, 4, 5},
+			2, 2,
+			[]interface{}{[]interface{}{1, 2}, []interface{}{3, 4}},
+		},
+		{
+			"test step greater than size",
+			[]int{1, 2, 3, 4, 5, 6, 7, 8},
+			2, 3,
+			[]interface{}{[]interface{}{1, 2}, []interface{}{4, 5}, []interface{}{7, 8}},
+		},
+	}
+	for _, newStream := range []func([]*element) Stream{newSequentialStreamForTest, newParallelStreamForTest} {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				res, err := newStream(intSliceToElements(tt.ints)).Window(tt.size, tt.step).CollectWith(ToSlice())
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expect, res)
+			})
+		}
+
+		t.Run("test size or step is not positive", func(t *testing.T) {
+			assert.EqualError(t, newStream(nil).Window(0, 1).Err(), "window error, size is not positive: 0")
+			assert.EqualError(t, newStream(nil).Window(1, -1).Err(), "window error, step is not positive: -1")
+		})
+	}
+}
+
+func TestNumberStreamWindow(t *testing.T) {
+	for _, s := range []IntStream{NewSequentialIntStream([]int{1, 2, 3, 4, 5}), NewParallelIntStream([]int{1, 2, 3, 4, 5}),
+		IntRangeClosed(1, 5)} {
+		var chunks [][]int
+		err := s.Chunk(2).Collect(&chunks)
+		assert.NoError(t, err)
+		assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, chunks)
+
+		var windows [][]int
+		err = s.Window(3, 1).Collect(&windows)
+		assert.NoError(t, err)
+		assert.Equal(t, [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}, windows)
+
+		averages, err := s.MovingAverage(2).Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []float64{1.5, 2.5, 3.5, 4.5}, averages)
+
+		averages, err = s.MovingAverage(6).Collect()
+		assert.NoError(t, err)
+		assert.Empty(t, averages)
+
+		assert.Error(t, s.Chunk(0).Err())
+		assert.Error(t, s.Window(1, 0).Err())
+		assert.EqualError(t, s.MovingAverage(0).Err(), "moving average error, size is not positive: 0")
+	}
+
+	t.Run("test windows are copies", func(t *testing.T) {
+		var windows [][]float64
+		err := NewSequentialFloat64Stream([]float64{1, 2, 3}).Window(2, 1).Collect(&windows)
+		assert.NoError(t, err)
+		windows[0][1] = 10
+		assert.Equal(t, []float64{2, 3}, windows[1])
+	})
+
+	t.Run("test float64 moving average", func(t *testing.T) {
+		averages, err := Float64Iterate(1, func(prev float64) float64 {
+			return prev * 2
+		}).MovingAverage(2).Limit(3).Collect()
+		assert.NoError(t, err)
+		assert.Equal(t, []float64{1.5, 3, 6}, averages)
+	})
+
+	assert.Error(t, testErrIntStream.Chunk(1).Err())
+	assert.Error(t, testErrIntStream.Window(1, 1).Err())
+	assert.Error(t, testErrIntStream.MovingAverage(1).Err())
+}
+
+func TestTypedStreamChunk(t *testing.T) {
+	s := NewParallelTypedStream([]string{"a", "b", "c", "d", "e"})
+	chunks, err := Chunk(s, 2).Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}, chunks)
+
+	windows, err := Window(s, 2, 3).Collect()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"a", "b"}, {"d", "e"}}, windows)
+}